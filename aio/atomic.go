@@ -0,0 +1,153 @@
+package aio
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CreateAtomic opens a file for writing, with a buffer.
+// Data is written to a temporary file in the same directory, which
+// replaces file when the writer is closed. Until then, and if closing
+// fails, file keeps its previous content.
+// Compresses the data according to the file's suffix.
+// The new file gets permissions 0644, regardless of the umask.
+func CreateAtomic(file string) (*AtomicWriter, error) {
+	f, err := createTemp(file, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	fn := wsuffixes[filepath.Ext(file)]
+	if fn == nil {
+		return &AtomicWriter{*bufio.NewWriter(f), nil, f, false}, nil
+	}
+	ff, err := fn(f)
+	if err != nil {
+		f.abort()
+		return nil, err
+	}
+	return &AtomicWriter{*bufio.NewWriter(ff), ff, f, false}, nil
+}
+
+// An AtomicWriter writes to a temporary file that replaces its target
+// when closed. Created by [CreateAtomic].
+type AtomicWriter struct {
+	bufio.Writer
+	z    io.WriteCloser // Compressor, or nil.
+	f    *atomicFile
+	done bool
+}
+
+// Close flushes the data and replaces the target file with the written
+// one. On failure, the temporary file is removed and the target is left
+// unchanged.
+func (w *AtomicWriter) Close() error {
+	if w.done {
+		return os.ErrClosed
+	}
+	w.done = true
+	if err := w.Flush(); err != nil {
+		w.abort()
+		return err
+	}
+	if w.z != nil {
+		if err := w.z.Close(); err != nil {
+			w.f.abort()
+			return err
+		}
+	}
+	return w.f.Close()
+}
+
+// Abort discards the written data and removes the temporary file,
+// leaving the target file unchanged. Does nothing if the writer was
+// already closed or aborted.
+func (w *AtomicWriter) Abort() {
+	if w.done {
+		return
+	}
+	w.done = true
+	w.abort()
+}
+
+// Closes the compressor and removes the temporary file.
+func (w *AtomicWriter) abort() {
+	if w.z != nil {
+		w.z.Close()
+	}
+	w.f.abort()
+}
+
+// WriteFileAtomic writes data to file, like [os.WriteFile], such that
+// file has either its previous content or the new content, even if the
+// process crashes. Does not compress the data.
+// Unlike [os.WriteFile], perm is applied as is, regardless of the umask.
+func WriteFileAtomic(file string, data []byte, perm os.FileMode) error {
+	f, err := createTemp(file, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.abort()
+		return err
+	}
+	return f.Close()
+}
+
+// A temporary file that replaces its target when closed.
+type atomicFile struct {
+	*os.File
+	target string
+	perm   os.FileMode
+}
+
+// Creates a temporary file next to target.
+func createTemp(target string, perm os.FileMode) (*atomicFile, error) {
+	dir, base := filepath.Split(target)
+	f, err := os.CreateTemp(dir, "."+base+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	return &atomicFile{f, target, perm}, nil
+}
+
+// Close flushes the file to disk and renames it to its target.
+// On failure, removes the temporary file.
+func (f *atomicFile) Close() error {
+	if err := f.Chmod(f.perm); err != nil {
+		f.abort()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.abort()
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.target); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	syncDir(filepath.Dir(f.target))
+	return nil
+}
+
+// Closes and removes the temporary file.
+func (f *atomicFile) abort() {
+	f.File.Close()
+	os.Remove(f.Name())
+}
+
+// Syncs a directory so that a rename in it is persisted.
+// Errors are ignored since not all platforms support it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,100 @@
+package aio
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	for _, want := range []string{"hello", "world!"} {
+		if err := WriteFileAtomic(file, []byte(want), 0o600); err != nil {
+			t.Fatalf("WriteFileAtomic(%q) failed: %v", want, err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("ReadFile(...)=%q, want %q", got, want)
+		}
+	}
+	stat, _ := os.Stat(file)
+	if stat.Mode().Perm() != 0o600 {
+		t.Errorf("mode=%v, want %v", stat.Mode().Perm(), os.FileMode(0o600))
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestCreateAtomic(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "a.txt.gz", "a.txt.zst"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		w, err := CreateAtomic(file)
+		if err != nil {
+			t.Fatalf("CreateAtomic(%q) failed: %v", name, err)
+		}
+		w.WriteString("new content")
+
+		// Before closing, the old content remains.
+		got, _ := os.ReadFile(file)
+		if string(got) != "old" {
+			t.Fatalf("ReadFile(%q) before Close=%q, want %q", name, got, "old")
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+
+		r, err := Open(file)
+		if err != nil {
+			t.Fatalf("Open(%q) failed: %v", name, err)
+		}
+		got, err = io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("ReadAll(%q) failed: %v", name, err)
+		}
+		if string(got) != "new content" {
+			t.Fatalf("ReadAll(%q)=%q, want %q", name, got, "new content")
+		}
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestCreateAtomic_abort(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "a.txt.gz"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		w, err := CreateAtomic(file)
+		if err != nil {
+			t.Fatalf("CreateAtomic(%q) failed: %v", name, err)
+		}
+		w.WriteString("new content")
+		w.Abort()
+		if err := w.Close(); err == nil {
+			t.Errorf("Close() after Abort() succeeded, want error")
+		}
+		got, _ := os.ReadFile(file)
+		if string(got) != "old" {
+			t.Fatalf("ReadFile(%q) after Abort=%q, want %q", name, got, "old")
+		}
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	tmp, _ := filepath.Glob(filepath.Join(dir, ".*.tmp*"))
+	if len(tmp) > 0 {
+		t.Errorf("temporary files left: %v", tmp)
+	}
+}
@@ -0,0 +1,209 @@
+// Package bitset provides a growable set of non-negative integers,
+// backed by a bit array.
+package bitset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// A Bitset is a growable bit array.
+// The zero value is an empty set ready for use.
+type Bitset struct {
+	w []uint64
+}
+
+// New returns an empty set with room for n bits.
+// The set grows as needed, so n is only a hint.
+func New(n int) *Bitset {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	return &Bitset{make([]uint64, 0, (n+63)/64)}
+}
+
+// Set sets bit i to 1 and returns b.
+func (b *Bitset) Set(i int) *Bitset {
+	checkIndex(i)
+	b.grow(i/64 + 1)
+	b.w[i/64] |= 1 << (i % 64)
+	return b
+}
+
+// Clear sets bit i to 0 and returns b.
+func (b *Bitset) Clear(i int) *Bitset {
+	checkIndex(i)
+	if i/64 < len(b.w) {
+		b.w[i/64] &^= 1 << (i % 64)
+	}
+	return b
+}
+
+// Test returns whether bit i is 1.
+func (b *Bitset) Test(i int) bool {
+	checkIndex(i)
+	if i/64 >= len(b.w) {
+		return false
+	}
+	return b.w[i/64]&(1<<(i%64)) != 0
+}
+
+// Len returns the number of bits that can be tested without growing the set.
+// Bits beyond Len are 0.
+func (b *Bitset) Len() int {
+	return len(b.w) * 64
+}
+
+// Count returns the number of bits that are 1.
+func (b *Bitset) Count() int {
+	n := 0
+	for _, w := range b.w {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// And sets b to the intersection of b and c, and returns b.
+func (b *Bitset) And(c *Bitset) *Bitset {
+	for i := range b.w {
+		if i < len(c.w) {
+			b.w[i] &= c.w[i]
+		} else {
+			b.w[i] = 0
+		}
+	}
+	return b
+}
+
+// Or sets b to the union of b and c, and returns b.
+func (b *Bitset) Or(c *Bitset) *Bitset {
+	b.grow(len(c.w))
+	for i, w := range c.w {
+		b.w[i] |= w
+	}
+	return b
+}
+
+// Xor sets b to the symmetric difference of b and c, and returns b.
+func (b *Bitset) Xor(c *Bitset) *Bitset {
+	b.grow(len(c.w))
+	for i, w := range c.w {
+		b.w[i] ^= w
+	}
+	return b
+}
+
+// AndNot removes the bits of c from b, and returns b.
+func (b *Bitset) AndNot(c *Bitset) *Bitset {
+	for i := range min(len(b.w), len(c.w)) {
+		b.w[i] &^= c.w[i]
+	}
+	return b
+}
+
+// Equal returns whether b and c have the same bits set.
+func (b *Bitset) Equal(c *Bitset) bool {
+	if len(b.w) < len(c.w) {
+		b, c = c, b
+	}
+	for i, w := range b.w {
+		if i < len(c.w) {
+			if w != c.w[i] {
+				return false
+			}
+		} else if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of b.
+func (b *Bitset) Clone() *Bitset {
+	return &Bitset{append([]uint64(nil), b.w...)}
+}
+
+// NextSet returns the index of the first 1 bit at index i or higher.
+// Returns false if there is no such bit.
+func (b *Bitset) NextSet(i int) (int, bool) {
+	checkIndex(i)
+	wi := i / 64
+	if wi >= len(b.w) {
+		return 0, false
+	}
+	w := b.w[wi] >> (i % 64)
+	if w != 0 {
+		return i + bits.TrailingZeros64(w), true
+	}
+	for wi++; wi < len(b.w); wi++ {
+		if b.w[wi] != 0 {
+			return wi*64 + bits.TrailingZeros64(b.w[wi]), true
+		}
+	}
+	return 0, false
+}
+
+// Ones iterates over the indexes of bits that are 1, in ascending order.
+func (b *Bitset) Ones() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.w {
+			for w != 0 {
+				j := bits.TrailingZeros64(w)
+				if !yield(i*64 + j) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// MarshalBinary implements the [encoding.BinaryMarshaler] interface.
+// The encoding is a varint word count followed by little-endian 64-bit
+// words, omitting trailing zero words.
+func (b *Bitset) MarshalBinary() ([]byte, error) {
+	n := len(b.w)
+	for n > 0 && b.w[n-1] == 0 {
+		n--
+	}
+	buf := binary.AppendUvarint(make([]byte, 0, n*8+binary.MaxVarintLen64),
+		uint64(n))
+	for _, w := range b.w[:n] {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the [encoding.BinaryUnmarshaler] interface.
+func (b *Bitset) UnmarshalBinary(data []byte) error {
+	n, m := binary.Uvarint(data)
+	if m <= 0 {
+		return fmt.Errorf("bad word count")
+	}
+	data = data[m:]
+	if len(data)%8 != 0 || n != uint64(len(data)/8) {
+		return fmt.Errorf("bad data length: %d for %d words", len(data), n)
+	}
+	w := make([]uint64, n)
+	for i := range w {
+		w[i] = binary.LittleEndian.Uint64(data[i*8:])
+	}
+	b.w = w
+	return nil
+}
+
+// Makes sure b has at least n words.
+func (b *Bitset) grow(n int) {
+	if n > len(b.w) {
+		b.w = append(b.w, make([]uint64, n-len(b.w))...)
+	}
+}
+
+// Panics if i is negative.
+func checkIndex(i int) {
+	if i < 0 {
+		panic(fmt.Sprintf("negative index: %d", i))
+	}
+}
@@ -0,0 +1,102 @@
+package bitset
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+func TestSetClear(t *testing.T) {
+	b := &Bitset{}
+	b.Set(1).Set(64).Set(200).Set(3)
+	b.Clear(3).Clear(1000)
+	for _, i := range []int{1, 64, 200} {
+		if !b.Test(i) {
+			t.Errorf("Test(%v)=false, want true", i)
+		}
+	}
+	for _, i := range []int{0, 2, 3, 63, 65, 199, 201, 5000} {
+		if b.Test(i) {
+			t.Errorf("Test(%v)=true, want false", i)
+		}
+	}
+	if got := b.Count(); got != 3 {
+		t.Errorf("Count()=%v, want 3", got)
+	}
+	if got, want := slices.Collect(b.Ones()), []int{1, 64, 200}; !slices.Equal(
+		got, want) {
+		t.Errorf("Ones()=%v, want %v", got, want)
+	}
+}
+
+func TestOps(t *testing.T) {
+	a := (&Bitset{}).Set(1).Set(2).Set(100)
+	b := (&Bitset{}).Set(2).Set(3)
+	tests := []struct {
+		name string
+		got  *Bitset
+		want []int
+	}{
+		{"And", a.Clone().And(b), []int{2}},
+		{"Or", a.Clone().Or(b), []int{1, 2, 3, 100}},
+		{"Xor", a.Clone().Xor(b), []int{1, 3, 100}},
+		{"AndNot", a.Clone().AndNot(b), []int{1, 100}},
+		{"AndNot2", b.Clone().AndNot(a), []int{3}},
+	}
+	for _, test := range tests {
+		if got := slices.Collect(test.got.Ones()); !slices.Equal(
+			got, test.want) {
+			t.Errorf("%s=%v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNextSet(t *testing.T) {
+	b := (&Bitset{}).Set(5).Set(70).Set(130)
+	tests := []struct {
+		i    int
+		want int
+		ok   bool
+	}{
+		{0, 5, true}, {5, 5, true}, {6, 70, true}, {71, 130, true},
+		{131, 0, false}, {1000, 0, false},
+	}
+	for _, test := range tests {
+		got, ok := b.NextSet(test.i)
+		if got != test.want || ok != test.ok {
+			t.Errorf("NextSet(%v)=%v,%v, want %v,%v",
+				test.i, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	b := (&Bitset{}).Set(0).Set(77).Set(1000).Clear(1000)
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() failed: %v", err)
+	}
+	if len(data) != 17 {
+		t.Errorf("MarshalBinary() len=%v, want 17", len(data))
+	}
+	got := &Bitset{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() failed: %v", err)
+	}
+	if !got.Equal(b) {
+		t.Fatalf("UnmarshalBinary()=%v, want %v",
+			slices.Collect(got.Ones()), slices.Collect(b.Ones()))
+	}
+}
+
+func TestUnmarshal_bad(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{1, 0, 0, 0},
+		binary.AppendUvarint(nil, 1<<61), // n*8 overflows to 0.
+	} {
+		if err := (&Bitset{}).UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary(%v) succeeded, want error", data)
+		}
+	}
+}
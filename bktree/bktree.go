@@ -0,0 +1,152 @@
+// Package bktree provides a BK-tree for approximate lookup in metric spaces.
+//
+// A BK-tree indexes values by their distances from one another,
+// so that a query for all values within a given distance from a target
+// needs to compute only a fraction of the distances.
+// A typical use is fuzzy dictionary matching using edit distance.
+//
+// The distance function must be a metric: non-negative, symmetric,
+// zero only for equal values and obeying the triangle inequality.
+package bktree
+
+import "iter"
+
+// A Tree is a BK-tree.
+type Tree[T any] struct {
+	root *node[T]
+	dist func(T, T) int
+	n    int
+}
+
+// A single node in the tree.
+type node[T any] struct {
+	t T
+	c map[int]*node[T] // Children by distance from t.
+}
+
+// New returns an empty tree that uses the given distance function.
+func New[T any](dist func(T, T) int) *Tree[T] {
+	return &Tree[T]{dist: dist}
+}
+
+// NewStrings returns an empty tree of strings that uses
+// the Levenshtein distance.
+func NewStrings() *Tree[string] {
+	return New(Levenshtein)
+}
+
+// Add inserts x to the tree.
+// Returns false if an element with a distance of 0 from x
+// already exists, in which case the tree is unchanged.
+func (t *Tree[T]) Add(x T) bool {
+	if t.root == nil {
+		t.root = &node[T]{x, nil}
+		t.n++
+		return true
+	}
+	cur := t.root
+	for {
+		d := t.dist(cur.t, x)
+		if d == 0 {
+			return false
+		}
+		next := cur.c[d]
+		if next == nil {
+			if cur.c == nil {
+				cur.c = map[int]*node[T]{}
+			}
+			cur.c[d] = &node[T]{x, nil}
+			t.n++
+			return true
+		}
+		cur = next
+	}
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree[T]) Len() int {
+	return t.n
+}
+
+// Query returns all elements whose distance from x is at most maxDist,
+// in no particular order.
+func (t *Tree[T]) Query(x T, maxDist int) []T {
+	var result []T
+	for y := range t.QuerySeq(x, maxDist) {
+		result = append(result, y)
+	}
+	return result
+}
+
+// QuerySeq iterates over all elements whose distance from x is at most
+// maxDist, along with their distances, in no particular order.
+func (t *Tree[T]) QuerySeq(x T, maxDist int) iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		if t.root == nil {
+			return
+		}
+		stack := []*node[T]{t.root}
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			d := t.dist(cur.t, x)
+			if d <= maxDist && !yield(cur.t, d) {
+				return
+			}
+			// By the triangle inequality, only children at distance
+			// d-maxDist..d+maxDist from cur may be close enough to x.
+			for cd, c := range cur.c {
+				if cd >= d-maxDist && cd <= d+maxDist {
+					stack = append(stack, c)
+				}
+			}
+		}
+	}
+}
+
+// All iterates over the elements of the tree, in no particular order.
+func (t *Tree[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if t.root == nil {
+			return
+		}
+		stack := []*node[T]{t.root}
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(cur.t) {
+				return
+			}
+			for _, c := range cur.c {
+				stack = append(stack, c)
+			}
+		}
+	}
+}
+
+// Levenshtein returns the edit distance between a and b,
+// counting insertions, deletions and substitutions of runes.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	// Keep only the previous row of the dynamic programming matrix.
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := range ra {
+		diag := row[0]
+		row[0] = i + 1
+		for j := range rb {
+			sub := diag
+			if ra[i] != rb[j] {
+				sub++
+			}
+			diag = row[j+1]
+			row[j+1] = min(sub, row[j]+1, row[j+1]+1)
+		}
+	}
+	return row[len(rb)]
+}
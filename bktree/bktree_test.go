@@ -0,0 +1,92 @@
+package bktree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"a", "", 1},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"שלום", "שלוםם", 1},
+	}
+	for _, test := range tests {
+		if got := Levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("Levenshtein(%q,%q)=%v, want %v",
+				test.a, test.b, got, test.want)
+		}
+		if got := Levenshtein(test.b, test.a); got != test.want {
+			t.Errorf("Levenshtein(%q,%q)=%v, want %v",
+				test.b, test.a, got, test.want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	words := []string{"book", "books", "cake", "boo", "boon", "cook",
+		"cape", "cart"}
+	tree := NewStrings()
+	for _, w := range words {
+		if !tree.Add(w) {
+			t.Fatalf("Add(%q)=false, want true", w)
+		}
+	}
+	if tree.Add("book") {
+		t.Fatalf("Add(%q)=true, want false", "book")
+	}
+	if tree.Len() != len(words) {
+		t.Fatalf("Len()=%v, want %v", tree.Len(), len(words))
+	}
+
+	tests := []struct {
+		q    string
+		d    int
+		want []string
+	}{
+		{"book", 0, []string{"book"}},
+		{"bo", 1, []string{"boo"}},
+		{"book", 1, []string{"boo", "book", "books", "boon", "cook"}},
+		{"cake", 1, []string{"cake", "cape"}},
+		{"xyz", 1, nil},
+	}
+	for _, test := range tests {
+		got := tree.Query(test.q, test.d)
+		slices.Sort(got)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Query(%q,%v)=%q, want %q", test.q, test.d, got, test.want)
+		}
+	}
+}
+
+func TestQuery_bruteForce(t *testing.T) {
+	words := []string{"alpha", "alphabet", "alpine", "beta", "better",
+		"bet", "gamma", "gammon", "game", "delta", "deltas", "dealt"}
+	tree := NewStrings()
+	for _, w := range words {
+		tree.Add(w)
+	}
+	for _, q := range []string{"alp", "bett", "gam", "deal", "x"} {
+		for d := range 5 {
+			var want []string
+			for _, w := range words {
+				if Levenshtein(q, w) <= d {
+					want = append(want, w)
+				}
+			}
+			slices.Sort(want)
+			got := tree.Query(q, d)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("Query(%q,%v)=%q, want %q", q, d, got, want)
+			}
+		}
+	}
+}
@@ -20,6 +20,7 @@ type Filter struct {
 	b    []byte        // Filter data.
 	h    []hash.Hash64 // Hash functions.
 	seed uint32
+	ro   bool // Read-only, for memory-mapped filters.
 }
 
 // NHash returns the number of hash functions this filter uses.
@@ -66,6 +67,7 @@ func (f *Filter) Has(v []byte) bool {
 // Add adds v to the filter, and returns the value of Has(v) before adding.
 // After calling Add, Has(v) will always be true. Makes k calls to hash.
 func (f *Filter) Add(v []byte) bool {
+	f.checkWritable()
 	has := true
 	for i := range f.h {
 		f.h[i].Reset()
@@ -82,6 +84,7 @@ func (f *Filter) Add(v []byte) bool {
 // AddFilter merges other into f. After merging, f is equivalent to have been added
 // all the elements of other.
 func (f *Filter) AddFilter(other *Filter) {
+	f.checkWritable()
 	// Make sure the two filters are compatible.
 	if f.NBits() != other.NBits() {
 		panic(fmt.Sprintf("mismatching number of bits: this has %v, other has %v",
@@ -0,0 +1,108 @@
+package bloom
+
+import (
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/fluhus/gostuff/internal/mmap"
+	"github.com/fluhus/gostuff/internal/sketchfmt"
+)
+
+// WriteTo writes the filter to w as a fixed-size header followed by the raw
+// bit array. The result can be read with ReadFrom or memory-mapped with
+// [Mmap]. Implements [io.WriterTo].
+func (f *Filter) WriteTo(w io.Writer) (int64, error) {
+	h := sketchfmt.Header{
+		Kind: sketchfmt.KindBloom,
+		P1:   uint32(len(f.h)),
+		P2:   f.seed,
+		N:    uint64(len(f.b)),
+	}
+	return sketchfmt.Write(w, h, f.b)
+}
+
+// ReadFrom reads a filter written by WriteTo and sets this filter's state
+// to match it. Destroys the previously existing state of this filter.
+// Implements [io.ReaderFrom].
+func (f *Filter) ReadFrom(r io.Reader) (int64, error) {
+	h, data, n, err := sketchfmt.ReadData(r, sketchfmt.KindBloom, checkHeader)
+	if err != nil {
+		return n, err
+	}
+	if err := f.setState(h, data); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// A Mapped is a read-only filter backed by a memory-mapped file.
+// Processes that map the same file share its memory, so large filters
+// can be loaded without copying.
+//
+// Calling Add or AddFilter on a mapped filter panics.
+type Mapped struct {
+	*Filter
+	data []byte
+}
+
+// Mmap maps a filter file written by WriteTo to memory.
+// The filter must be released with Close.
+func Mmap(file string) (*Mapped, error) {
+	data, err := mmap.Map(file)
+	if err != nil {
+		return nil, err
+	}
+	h, b, err := sketchfmt.Map(data, sketchfmt.KindBloom)
+	if err != nil {
+		mmap.Unmap(data)
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	f := &Filter{}
+	if err := f.setState(h, b); err != nil {
+		mmap.Unmap(data)
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	f.ro = true
+	return &Mapped{f, data}, nil
+}
+
+// Close unmaps the filter's file. The filter must not be used after
+// calling Close.
+func (m *Mapped) Close() error {
+	return mmap.Unmap(m.data)
+}
+
+// Maximal number of hashes accepted when decoding a filter.
+const maxHashes = 1 << 10
+
+// Checks that a header describes a valid filter.
+func checkHeader(h sketchfmt.Header) error {
+	if h.P1 < 1 || h.P1 > maxHashes {
+		return fmt.Errorf("bad number of hashes: %d", h.P1)
+	}
+	if h.N == 0 {
+		return fmt.Errorf("empty filter data")
+	}
+	return nil
+}
+
+// Sets the filter's parameters and data according to a decoded header.
+func (f *Filter) setState(h sketchfmt.Header, data []byte) error {
+	if err := checkHeader(h); err != nil {
+		return err
+	}
+	f.b = nil
+	f.h = make([]hash.Hash64, h.P1)
+	f.SetSeed(h.P2)
+	f.b = data
+	f.ro = false
+	return nil
+}
+
+// Panics if the filter is read-only.
+func (f *Filter) checkWritable() {
+	if f.ro {
+		panic("filter is read-only")
+	}
+}
@@ -0,0 +1,92 @@
+package bloom
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	f1 := New(1000, 3)
+	for i := range 50 {
+		f1.Add([]byte{byte(i)})
+	}
+	buf := bytes.NewBuffer(nil)
+	n, err := f1.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("WriteTo(...) failed: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo(...)=%v, want %v", n, buf.Len())
+	}
+	f2 := &Filter{}
+	m, err := f2.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom(...) failed: %v", err)
+	}
+	if m != n {
+		t.Fatalf("ReadFrom(...)=%v, want %v", m, n)
+	}
+	if !bytes.Equal(f1.b, f2.b) || f1.seed != f2.seed || f1.NHash() != f2.NHash() {
+		t.Fatalf("ReadFrom(...) state mismatch")
+	}
+	for i := range 256 {
+		if f1.Has([]byte{byte(i)}) != f2.Has([]byte{byte(i)}) {
+			t.Fatalf("Has(%v) mismatch", i)
+		}
+	}
+}
+
+func TestReadFromBad(t *testing.T) {
+	f1 := New(80, 2)
+	buf := bytes.NewBuffer(nil)
+	f1.WriteTo(buf)
+	b := buf.Bytes()
+
+	if _, err := (&Filter{}).ReadFrom(bytes.NewReader(b[:len(b)-1])); err == nil {
+		t.Errorf("ReadFrom(truncated) succeeded, want error")
+	}
+	bad := bytes.Clone(b)
+	bad[0] = 'X'
+	if _, err := (&Filter{}).ReadFrom(bytes.NewReader(bad)); err == nil {
+		t.Errorf("ReadFrom(bad magic) succeeded, want error")
+	}
+	bad = bytes.Clone(b)
+	bad[5] = 2
+	if _, err := (&Filter{}).ReadFrom(bytes.NewReader(bad)); err == nil {
+		t.Errorf("ReadFrom(bad kind) succeeded, want error")
+	}
+}
+
+func TestMmap(t *testing.T) {
+	f1 := New(10000, 4)
+	for i := range 100 {
+		f1.Add([]byte{byte(i), 1})
+	}
+	file := filepath.Join(t.TempDir(), "filter")
+	buf := bytes.NewBuffer(nil)
+	f1.WriteTo(buf)
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f2, err := Mmap(file)
+	if err != nil {
+		t.Fatalf("Mmap(...) failed: %v", err)
+	}
+	defer f2.Close()
+	for i := range 256 {
+		v := []byte{byte(i), 1}
+		if f1.Has(v) != f2.Has(v) {
+			t.Fatalf("Has(%v) mismatch", v)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Add on mapped filter did not panic")
+		}
+	}()
+	f2.Add([]byte{1})
+}
@@ -0,0 +1,121 @@
+package bnry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+var encodings = []Encoding{Raw, Varint, Delta, Shuffle}
+
+func TestCompact(t *testing.T) {
+	testCompact(t, []uint8{0, 1, 255, 3, 3})
+	testCompact(t, []uint16{0, 1, math.MaxUint16, 3})
+	testCompact(t, []uint32{7, 5, math.MaxUint32, 0})
+	testCompact(t, []uint64{1, math.MaxUint64, 0, 1 << 40})
+	testCompact(t, []int8{-128, 127, 0, -1})
+	testCompact(t, []int16{-1, math.MinInt16, math.MaxInt16})
+	testCompact(t, []int32{-5, 1000000, math.MinInt32, math.MaxInt32})
+	testCompact(t, []int64{math.MinInt64, math.MaxInt64, 0, -1})
+	testCompact(t, []int{1, -2, 3, -4})
+	testCompact(t, []float32{0, -1.5, 3.25, float32(math.Inf(1))})
+	testCompact(t, []float64{math.Pi, math.E, -0.5, 1e300, math.Inf(-1)})
+	testCompact(t, []float64{})
+}
+
+func testCompact[T Number](t *testing.T, s []T) {
+	t.Helper()
+	for _, enc := range encodings {
+		buf := AppendCompact([]byte{42}, s, enc)
+		got, n, err := DecodeCompact[T](buf[1:])
+		if err != nil {
+			t.Fatalf("DecodeCompact(%v %v) failed: %v", s, enc, err)
+		}
+		if n != len(buf)-1 {
+			t.Errorf("DecodeCompact(%v %v) read %d bytes, want %d",
+				s, enc, n, len(buf)-1)
+		}
+		if !slices.Equal(got, s) {
+			t.Errorf("DecodeCompact(%v)=%v, want %v", enc, got, s)
+		}
+
+		w := bytes.NewBuffer(nil)
+		if err := WriteCompact(w, s, enc); err != nil {
+			t.Fatalf("WriteCompact(%v %v) failed: %v", s, enc, err)
+		}
+		got, err = ReadCompact[T](w)
+		if err != nil {
+			t.Fatalf("ReadCompact(%v %v) failed: %v", s, enc, err)
+		}
+		if !slices.Equal(got, s) {
+			t.Errorf("ReadCompact(%v)=%v, want %v", enc, got, s)
+		}
+	}
+}
+
+func TestCompact_random(t *testing.T) {
+	for range 100 {
+		n := rand.IntN(100)
+		u := make([]uint32, n)
+		f := make([]float32, n)
+		for i := range n {
+			u[i] = rand.Uint32()
+			f[i] = rand.Float32()
+		}
+		testCompact(t, u)
+		testCompact(t, f)
+	}
+}
+
+func TestCompact_size(t *testing.T) {
+	s := make([]uint64, 1000)
+	for i := range s {
+		s[i] = 1<<40 + uint64(i*3)
+	}
+	raw := len(AppendCompact(nil, s, Raw))
+	varint := len(AppendCompact(nil, s, Varint))
+	delta := len(AppendCompact(nil, s, Delta))
+	if delta >= varint || varint >= raw {
+		t.Errorf("sizes raw=%d varint=%d delta=%d, want decreasing",
+			raw, varint, delta)
+	}
+}
+
+func TestCompact_bad(t *testing.T) {
+	buf := AppendCompact(nil, []int32{1, 2, 3}, Varint)
+	if _, _, err := DecodeCompact[uint32](buf); err == nil {
+		t.Errorf("DecodeCompact[uint32](int32 data) succeeded, want error")
+	}
+	if _, _, err := DecodeCompact[int64](buf); err == nil {
+		t.Errorf("DecodeCompact[int64](int32 data) succeeded, want error")
+	}
+	for i := range len(buf) - 1 {
+		if _, _, err := DecodeCompact[int32](buf[:i]); err == nil {
+			t.Errorf("DecodeCompact(%v) succeeded, want error", buf[:i])
+		}
+		if _, err := ReadCompact[int32](bytes.NewBuffer(buf[:i])); err == nil {
+			t.Errorf("ReadCompact(%v) succeeded, want error", buf[:i])
+		}
+	}
+	bad := slices.Clone(buf)
+	bad[0] = 2
+	if _, _, err := DecodeCompact[int32](bad); err == nil {
+		t.Errorf("DecodeCompact(%v) succeeded, want error", bad)
+	}
+	bad[0], bad[2] = buf[0], 9
+	if _, _, err := DecodeCompact[int32](bad); err == nil {
+		t.Errorf("DecodeCompact(%v) succeeded, want error", bad)
+	}
+
+	// Huge lengths with a short payload should fail without allocating.
+	huge := []byte{buf[0], buf[1], buf[2]}
+	huge = binary.AppendUvarint(huge, 1<<40)
+	huge = binary.AppendUvarint(huge, 1<<50)
+	huge = append(huge, 1, 2, 3)
+	if _, err := ReadCompact[int32](bytes.NewBuffer(huge)); err == nil {
+		t.Errorf("ReadCompact(%v) succeeded, want error", huge)
+	}
+}
@@ -8,4 +8,11 @@
 // slices of these types.
 // [Read] and [UnmarshalBinary] expect pointers to these types,
 // while [Write] and [MarshalBinary] expect non-pointers.
+//
+// # Compact encodings
+//
+// Large numeric slices can be written with [AppendCompact] and
+// [WriteCompact], using one of several [Encoding] schemes.
+// The output has a versioned header and is read back with
+// [DecodeCompact] and [ReadCompact].
 package bnry
@@ -0,0 +1,162 @@
+// Package clone provides deep copying of arbitrary values.
+package clone
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// A Cloner can make a deep copy of itself.
+//
+// [DeepCopy] uses the Clone method of values whose type has a Clone method
+// with no arguments that returns the same type, instead of copying them
+// field by field.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// DeepCopy returns a deep copy of x.
+//
+// Pointers, slices, maps, arrays, structs (including unexported fields)
+// and interfaces are copied recursively. Shared references and cycles are
+// preserved: a pointer, slice or map that appears several times in x
+// is copied once. Map keys, channels, functions and unsafe pointers are
+// copied shallowly.
+func DeepCopy[T any](x T) T {
+	c := &copier{map[visitKey]reflect.Value{}}
+	var y T
+	reflect.ValueOf(&y).Elem().Set(c.copy(reflect.ValueOf(&x).Elem()))
+	return y
+}
+
+// Identifies a visited reference.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	n   int // Slice length.
+}
+
+// Holds the state of a single deep copy.
+type copier struct {
+	seen map[visitKey]reflect.Value
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Returns a deep copy of v.
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if t.Kind() != reflect.Interface && t != timeType {
+		if m, ok := cloneMethod(t); ok && !isNil(v) {
+			return m.Func.Call([]reflect.Value{v})[0]
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{v.Pointer(), t, 0}
+		if dup, ok := c.seen[key]; ok {
+			return dup
+		}
+		out := reflect.New(t.Elem())
+		c.seen[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		out := reflect.New(t).Elem()
+		out.Set(c.copy(v.Elem()))
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{v.Pointer(), t, v.Len()}
+		if dup, ok := c.seen[key]; ok {
+			return dup
+		}
+		out := reflect.MakeSlice(t, v.Len(), v.Cap())
+		c.seen[key] = out
+		for i := range v.Len() {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(t).Elem()
+		for i := range v.Len() {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(t)
+		}
+		key := visitKey{v.Pointer(), t, 0}
+		if dup, ok := c.seen[key]; ok {
+			return dup
+		}
+		out := reflect.MakeMapWithSize(t, v.Len())
+		c.seen[key] = out
+		for it := v.MapRange(); it.Next(); {
+			out.SetMapIndex(it.Key(), c.copy(it.Value()))
+		}
+		return out
+
+	case reflect.Struct:
+		if t == timeType {
+			return v
+		}
+		src := v
+		if !src.CanAddr() {
+			src = reflect.New(t).Elem()
+			src.Set(v)
+		}
+		out := reflect.New(t).Elem()
+		for i := range t.NumField() {
+			accessible(out.Field(i)).Set(c.copy(accessible(src.Field(i))))
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+// Returns t's Clone method, if it has one that returns t.
+func cloneMethod(t reflect.Type) (reflect.Method, bool) {
+	m, ok := t.MethodByName("Clone")
+	if !ok {
+		return m, false
+	}
+	ft := m.Type
+	if ft.NumIn() != 1 || ft.NumOut() != 1 || ft.Out(0) != t {
+		return m, false
+	}
+	return m, true
+}
+
+// Returns whether v is a nil reference.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface,
+		reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Returns a settable view of an addressable value, even if it was
+// obtained through unexported fields.
+func accessible(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
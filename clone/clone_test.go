@@ -0,0 +1,101 @@
+package clone
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluhus/gostuff/bitset"
+)
+
+type node struct {
+	Val      int
+	Next     *node
+	children []*node
+	tags     map[string][]int
+	Any      any
+	At       time.Time
+}
+
+func TestDeepCopy(t *testing.T) {
+	n := &node{Val: 1, tags: map[string][]int{"a": {1, 2}}, Any: []string{"x"},
+		At: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	n.children = []*node{{Val: 2}, {Val: 3}}
+	n.Next = n.children[0]
+
+	c := DeepCopy(n)
+	if !reflect.DeepEqual(c, n) {
+		t.Fatalf("DeepCopy(%v)=%v, want equal", n, c)
+	}
+	if c == n || c.children[0] == n.children[0] {
+		t.Fatalf("DeepCopy(...) shares pointers with input")
+	}
+	if c.Next != c.children[0] {
+		t.Errorf("DeepCopy(...) did not preserve shared pointer")
+	}
+
+	// Modifying the copy should not affect the input.
+	c.tags["a"][0] = 100
+	c.Any.([]string)[0] = "y"
+	c.children[1].Val = 300
+	if n.tags["a"][0] != 1 || n.Any.([]string)[0] != "x" ||
+		n.children[1].Val != 3 {
+		t.Errorf("modifying copy changed input: %+v", n)
+	}
+}
+
+func TestDeepCopy_cycle(t *testing.T) {
+	n := &node{Val: 1}
+	n.Next = &node{Val: 2, Next: n}
+	c := DeepCopy(n)
+	if c == n || c.Next == n.Next {
+		t.Fatalf("DeepCopy(...) shares pointers with input")
+	}
+	if c.Next.Next != c || c.Next.Val != 2 {
+		t.Errorf("DeepCopy(...) did not preserve cycle")
+	}
+}
+
+func TestDeepCopy_basic(t *testing.T) {
+	inputs := []any{
+		5, "hello", []int{1, 2}, map[int]string{1: "a"}, [2][]int{{1}, {2}},
+		[]any{1, "a", nil}, (*int)(nil), []int(nil),
+	}
+	for _, input := range inputs {
+		if got := DeepCopy(input); !reflect.DeepEqual(got, input) {
+			t.Errorf("DeepCopy(%v)=%v", input, got)
+		}
+	}
+	var nilAny any
+	if got := DeepCopy(nilAny); got != nil {
+		t.Errorf("DeepCopy(nil)=%v, want nil", got)
+	}
+}
+
+type counted struct {
+	n *int
+}
+
+func (c *counted) Clone() *counted {
+	*c.n++
+	return &counted{c.n}
+}
+
+func TestDeepCopy_cloner(t *testing.T) {
+	n := 0
+	x := []*counted{{&n}, {&n}}
+	c := DeepCopy(x)
+	if n != 2 {
+		t.Errorf("Clone called %d times, want 2", n)
+	}
+	if c[0].n != &n {
+		t.Errorf("DeepCopy(...) did not use Clone")
+	}
+
+	b := bitset.New(0).Set(3)
+	bc := DeepCopy(b)
+	bc.Set(5)
+	if b.Test(5) || !bc.Test(3) {
+		t.Errorf("DeepCopy(bitset) is not independent")
+	}
+}
@@ -6,8 +6,10 @@
 // # Decode Accepted Types
 //
 // The T type parameter for Decode functions accepts structs.
-// Fields may be of types bool, int*, uint*, float* or string
-// for automatic parsing.
+// Fields may be of types bool, int*, uint*, float*, string,
+// [time.Time] or [time.Duration] for automatic parsing.
+// Times are parsed as RFC 3339 by default, and durations are parsed
+// with [time.ParseDuration].
 // For manual parsing with a method, any type is allowed.
 // Unexported fields are ignored.
 //
@@ -54,6 +56,8 @@
 //   - "allowempty": the input value may be empty, in which case no parsing
 //     will be attempted
 //   - "optional": don't err if the column for this field is missing
+//   - "layout=...": parse a [time.Time] field with the given [time.Parse]
+//     layout, which cannot contain commas
 //   - exported method name: use T's method with this name to parse the
 //     input value
 //
@@ -69,6 +73,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // TODO(amit): Struct pointers?
@@ -174,6 +179,7 @@ func matchColToField(t reflect.Type, cols []string) (map[int][]setter, error) {
 		}
 		name, m, mi := strings.ToLower(f.Name), ci, cif
 		allowEmpty, optional, parseFunc := false, false, false
+		layout := time.RFC3339
 		parts := strings.Split(f.Tag.Get("csvx"), ",")
 		if tag := parts[0]; tag != "" {
 			if tag == "-" {
@@ -190,6 +196,10 @@ func matchColToField(t reflect.Type, cols []string) (map[int][]setter, error) {
 				allowEmpty = true
 				continue
 			}
+			if l, ok := strings.CutPrefix(p, "layout="); ok {
+				layout = l
+				continue
+			}
 			if p == "optional" {
 				optional = true
 				continue
@@ -219,60 +229,8 @@ func matchColToField(t reflect.Type, cols []string) (map[int][]setter, error) {
 		if parseFunc {
 			continue
 		}
-		switch f.Type.Kind() {
-		case reflect.String:
-			m[name] = append(m[name], func(dst reflect.Value, src string) error {
-				dst.Field(i).SetString(src)
-				return nil
-			})
-		case reflect.Float32, reflect.Float64:
-			m[name] = append(m[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseFloat(src, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetFloat(x)
-				return nil
-			})
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			m[name] = append(m[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseInt(src, 0, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetInt(x)
-				return nil
-			})
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			m[name] = append(m[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseUint(src, 0, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetUint(x)
-				return nil
-			})
-		case reflect.Bool:
-			m[name] = append(m[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseBool(src)
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetBool(x)
-				return nil
-			})
+		if st := fieldSetter(f, i, allowEmpty, layout); st != nil {
+			m[name] = append(m[name], st)
 		}
 	}
 	m := map[int][]setter{}
@@ -320,6 +278,7 @@ func matchColToFieldNoHeader(t reflect.Type) (map[int][]setter, error) {
 		}
 		name := fmt.Sprint(cur)
 		allowEmpty, parseFunc := false, false
+		layout := time.RFC3339
 		parts := strings.Split(f.Tag.Get("csvx"), ",")
 		if tag := parts[0]; tag != "" {
 			if tag == "-" {
@@ -339,6 +298,10 @@ func matchColToFieldNoHeader(t reflect.Type) (map[int][]setter, error) {
 				allowEmpty = true
 				continue
 			}
+			if l, ok := strings.CutPrefix(p, "layout="); ok {
+				layout = l
+				continue
+			}
 			method, ok := t.MethodByName(p)
 			if !ok {
 				return nil, fmt.Errorf("method not found: %v", p)
@@ -363,60 +326,8 @@ func matchColToFieldNoHeader(t reflect.Type) (map[int][]setter, error) {
 		if parseFunc {
 			continue
 		}
-		switch f.Type.Kind() {
-		case reflect.String:
-			ii[name] = append(ii[name], func(dst reflect.Value, src string) error {
-				dst.Field(i).SetString(src)
-				return nil
-			})
-		case reflect.Float32, reflect.Float64:
-			ii[name] = append(ii[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseFloat(src, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetFloat(x)
-				return nil
-			})
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			ii[name] = append(ii[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseInt(src, 0, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetInt(x)
-				return nil
-			})
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			ii[name] = append(ii[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseUint(src, 0, f.Type.Bits())
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetUint(x)
-				return nil
-			})
-		case reflect.Bool:
-			ii[name] = append(ii[name], func(dst reflect.Value, src string) error {
-				if allowEmpty && src == "" {
-					return nil
-				}
-				x, err := strconv.ParseBool(src)
-				if err != nil {
-					return err
-				}
-				dst.Field(i).SetBool(x)
-				return nil
-			})
+		if st := fieldSetter(f, i, allowEmpty, layout); st != nil {
+			ii[name] = append(ii[name], st)
 		}
 	}
 	m := map[int][]setter{}
@@ -428,6 +339,96 @@ func matchColToFieldNoHeader(t reflect.Type) (map[int][]setter, error) {
 	return m, nil
 }
 
+// Returns a setter that parses a value according to the type of field f,
+// whose index is i. Returns nil if the type is not supported.
+func fieldSetter(f reflect.StructField, i int, allowEmpty bool,
+	layout string) setter {
+	// Check special types before kinds, since a duration's kind is int64.
+	switch f.Type {
+	case reflect.TypeFor[time.Time]():
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := time.Parse(layout, src)
+			if err != nil {
+				return err
+			}
+			dst.Field(i).Set(reflect.ValueOf(x))
+			return nil
+		}
+	case reflect.TypeFor[time.Duration]():
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := time.ParseDuration(src)
+			if err != nil {
+				return err
+			}
+			dst.Field(i).SetInt(int64(x))
+			return nil
+		}
+	}
+
+	switch f.Type.Kind() {
+	case reflect.String:
+		return func(dst reflect.Value, src string) error {
+			dst.Field(i).SetString(src)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := strconv.ParseFloat(src, f.Type.Bits())
+			if err != nil {
+				return err
+			}
+			dst.Field(i).SetFloat(x)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := strconv.ParseInt(src, 0, f.Type.Bits())
+			if err != nil {
+				return err
+			}
+			dst.Field(i).SetInt(x)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := strconv.ParseUint(src, 0, f.Type.Bits())
+			if err != nil {
+				return err
+			}
+			dst.Field(i).SetUint(x)
+			return nil
+		}
+	case reflect.Bool:
+		return func(dst reflect.Value, src string) error {
+			if allowEmpty && src == "" {
+				return nil
+			}
+			x, err := strconv.ParseBool(src)
+			if err != nil {
+				return err
+			}
+			dst.Field(i).SetBool(x)
+			return nil
+		}
+	}
+	return nil
+}
+
 // Populates a's fields given the input values and setter-map.
 func populateStruct(a any, vals []string, setters map[int][]setter) error {
 	v := reflect.ValueOf(a).Elem()
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestDecodeReader_basic(t *testing.T) {
@@ -106,6 +107,22 @@ func TestDecodeReader_bad(t *testing.T) {
 	testGeneric(t, true, []testCase[string]{{"stringy\n", "", true}})
 }
 
+func TestDecodeReader_time(t *testing.T) {
+	tests := []testCase[timeItem]{
+		{"at,day,took\n2024-03-01T10:00:00Z,2024-03-02,1m30s",
+			timeItem{
+				At:   time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+				Day:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+				Took: 90 * time.Second,
+			}, false},
+		{"at,day,took\n,,", timeItem{}, false},
+		{"at,day,took\n2024-03-01,2024-03-02,1m", timeItem{}, true},
+		{"at,day,took\n2024-03-01T10:00:00Z,2024/03/02,1m", timeItem{}, true},
+		{"at,day,took\n2024-03-01T10:00:00Z,2024-03-02,90", timeItem{}, true},
+	}
+	testGeneric(t, true, tests)
+}
+
 func testGeneric[T any](t *testing.T, header bool, tests []testCase[T]) {
 tloop:
 	for i, test := range tests {
@@ -136,6 +153,12 @@ tloop:
 	}
 }
 
+type timeItem struct {
+	At   time.Time     `csvx:",allowempty"`
+	Day  time.Time     `csvx:",layout=2006-01-02,allowempty"`
+	Took time.Duration `csvx:",allowempty"`
+}
+
 type testCase[T any] struct {
 	input   string
 	want    T
@@ -0,0 +1,233 @@
+package csvx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluhus/gostuff/aio"
+)
+
+// An Encoder writes structs of type T as delimiter-separated lines.
+//
+// Each exported field is a column, titled by the field's name or by its
+// csvx tag's column part if it is not numeric. Fields tagged "-" are
+// skipped, and "layout=..." modifiers set the format of [time.Time]
+// fields, so that the output can be decoded back with the Decode
+// functions.
+type Encoder[T any] struct {
+	// Format and precision of floats, as in [strconv.FormatFloat].
+	// The defaults are 'g' and -1.
+	FloatFormat    byte
+	FloatPrecision int
+
+	base
+	fields []encField
+}
+
+// Common state of encoders.
+type base struct {
+	w   *csv.Writer
+	c   io.Closer // Nil if the encoder does not own the writer.
+	row []string
+}
+
+// A struct field to encode.
+type encField struct {
+	i      int
+	name   string
+	layout string
+}
+
+// WriterModifier modifies the settings of a CSV writer
+// before writing starts.
+type WriterModifier = func(*csv.Writer)
+
+// TSVWriter makes the writer use tab as the delimiter.
+func TSVWriter(w *csv.Writer) {
+	w.Comma = '\t'
+}
+
+// NewEncoder returns an encoder that writes to w.
+// Applies the given modifiers before writing.
+// Panics if T is not a struct.
+func NewEncoder[T any](w io.Writer, mods ...WriterModifier) *Encoder[T] {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("expected struct, got %v", t))
+	}
+	var fields []encField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		ef := encField{i: i, name: f.Name, layout: time.RFC3339}
+		parts := strings.Split(f.Tag.Get("csvx"), ",")
+		if tag := parts[0]; tag == "-" {
+			continue
+		} else if tag != "" && !numeric(tag) {
+			ef.name = tag
+		}
+		for _, p := range parts[1:] {
+			if l, ok := strings.CutPrefix(p, "layout="); ok {
+				ef.layout = l
+			}
+		}
+		fields = append(fields, ef)
+	}
+	return &Encoder[T]{FloatFormat: 'g', FloatPrecision: -1,
+		base: newBase(w, mods), fields: fields}
+}
+
+// Returns an encoder base that writes to w.
+func newBase(w io.Writer, mods []WriterModifier) base {
+	c := csv.NewWriter(w)
+	for _, mod := range mods {
+		mod(c)
+	}
+	return base{w: c}
+}
+
+// CreateEncoder returns an encoder that writes to the given file.
+// Compresses the data according to the file's suffix.
+// The encoder must be closed to flush the data.
+func CreateEncoder[T any](file string, mods ...WriterModifier,
+) (*Encoder[T], error) {
+	f, err := aio.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	e := NewEncoder[T](f, mods...)
+	e.c = f
+	return e, nil
+}
+
+// WriteHeader writes a line with the column titles.
+func (e *Encoder[T]) WriteHeader() error {
+	e.row = e.row[:0]
+	for _, f := range e.fields {
+		e.row = append(e.row, f.name)
+	}
+	return e.w.Write(e.row)
+}
+
+// Encode writes t as a single line.
+func (e *Encoder[T]) Encode(t T) error {
+	v := reflect.ValueOf(t)
+	e.row = e.row[:0]
+	for _, f := range e.fields {
+		e.row = append(e.row, format(v.Field(f.i), f.layout,
+			e.FloatFormat, e.FloatPrecision))
+	}
+	return e.w.Write(e.row)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (e *base) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Close flushes the data, and closes the underlying file if the encoder
+// was created with a Create function.
+func (e *base) Close() error {
+	if err := e.Flush(); err != nil {
+		if e.c != nil {
+			e.c.Close()
+		}
+		return err
+	}
+	if e.c == nil {
+		return nil
+	}
+	return e.c.Close()
+}
+
+// A MapEncoder writes maps as delimiter-separated lines, with a fixed
+// list of columns. Values are formatted like an [Encoder]'s fields.
+type MapEncoder[V any] struct {
+	// Format and precision of floats, as in [strconv.FormatFloat].
+	// The defaults are 'g' and -1.
+	FloatFormat    byte
+	FloatPrecision int
+
+	base
+	cols []string
+}
+
+// NewMapEncoder returns an encoder that writes the given columns to w.
+// Applies the given modifiers before writing.
+func NewMapEncoder[V any](w io.Writer, cols []string,
+	mods ...WriterModifier) *MapEncoder[V] {
+	return &MapEncoder[V]{FloatFormat: 'g', FloatPrecision: -1,
+		base: newBase(w, mods), cols: cols}
+}
+
+// CreateMapEncoder returns an encoder that writes the given columns to
+// the given file. Compresses the data according to the file's suffix.
+// The encoder must be closed to flush the data.
+func CreateMapEncoder[V any](file string, cols []string,
+	mods ...WriterModifier) (*MapEncoder[V], error) {
+	f, err := aio.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	e := NewMapEncoder[V](f, cols, mods...)
+	e.c = f
+	return e, nil
+}
+
+// WriteHeader writes a line with the column titles.
+func (e *MapEncoder[V]) WriteHeader() error {
+	return e.w.Write(e.cols)
+}
+
+// Encode writes the values of m's columns as a single line.
+// Missing columns are written as empty values.
+func (e *MapEncoder[V]) Encode(m map[string]V) error {
+	e.row = e.row[:0]
+	for _, col := range e.cols {
+		v, ok := m[col]
+		if !ok {
+			e.row = append(e.row, "")
+			continue
+		}
+		e.row = append(e.row, format(reflect.ValueOf(&v).Elem(), time.RFC3339,
+			e.FloatFormat, e.FloatPrecision))
+	}
+	return e.w.Write(e.row)
+}
+
+// Returns the string representation of a value.
+func format(v reflect.Value, layout string, ffmt byte, fprec int) string {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case reflect.TypeFor[time.Time]():
+		return v.Interface().(time.Time).Format(layout)
+	case reflect.TypeFor[time.Duration]():
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), ffmt, fprec, v.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,95 @@
+package csvx
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type encItem struct {
+	Name    string
+	Count   int     `csvx:"cnt"`
+	Score   float64 `csvx:"2"`
+	Skipped string  `csvx:"-"`
+	OK      bool
+	Day     time.Time     `csvx:",layout=2006-01-02"`
+	Took    time.Duration `csvx:""`
+	private int
+}
+
+func TestEncoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	e := NewEncoder[encItem](buf)
+	e.WriteHeader()
+	day := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	e.Encode(encItem{"a,b", 3, 0.125, "x", true, day, time.Minute, 1})
+	e.FloatFormat, e.FloatPrecision = 'f', 2
+	e.Encode(encItem{Name: "c", Score: 1.0 / 3, Day: day})
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	want := "Name,cnt,Score,OK,Day,Took\n" +
+		"\"a,b\",3,0.125,true,2024-05-06,1m0s\n" +
+		"c,0,0.33,false,2024-05-06,0s\n"
+	if buf.String() != want {
+		t.Fatalf("Encode(...)=%q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	type item struct {
+		Name string
+		Age  int `csvx:"age"`
+		At   time.Time
+	}
+	want := []item{
+		{"alice", 30, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"bob", 40, time.Date(2023, 6, 7, 8, 9, 10, 0, time.UTC)},
+	}
+	file := filepath.Join(t.TempDir(), "a.tsv.gz")
+	e, err := CreateEncoder[item](file, TSVWriter)
+	if err != nil {
+		t.Fatalf("CreateEncoder(...) failed: %v", err)
+	}
+	e.WriteHeader()
+	for _, x := range want {
+		e.Encode(x)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	var got []item
+	for x, err := range DecodeFileHeader[item](file, TSV) {
+		if err != nil {
+			t.Fatalf("DecodeFileHeader(...) failed: %v", err)
+		}
+		got = append(got, x)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DecodeFileHeader(...)=%v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name || got[i].Age != want[i].Age ||
+			!got[i].At.Equal(want[i].At) {
+			t.Fatalf("DecodeFileHeader(...)=%v, want %v", got, want)
+		}
+	}
+}
+
+func TestMapEncoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	e := NewMapEncoder[any](buf, []string{"a", "b", "c"}, TSVWriter)
+	e.FloatFormat, e.FloatPrecision = 'f', 1
+	e.WriteHeader()
+	e.Encode(map[string]any{"a": 1, "b": 2.25, "c": "x"})
+	e.Encode(map[string]any{"c": true, "d": 5})
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	want := "a\tb\tc\n1\t2.2\tx\n\t\ttrue\n"
+	if buf.String() != want {
+		t.Fatalf("Encode(...)=%q, want %q", buf.String(), want)
+	}
+}
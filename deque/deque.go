@@ -0,0 +1,199 @@
+// Package deque provides a growable double-ended queue
+// and a fixed-capacity ring buffer.
+package deque
+
+import (
+	"fmt"
+	"iter"
+)
+
+// A Deque is a growable double-ended queue.
+// The zero value is an empty deque ready for use.
+type Deque[T any] struct {
+	a    []T
+	i, n int // Start index and number of elements.
+}
+
+// PushBack adds x at the back of the deque.
+func (d *Deque[T]) PushBack(x T) {
+	d.growIfFull()
+	d.a[(d.i+d.n)%len(d.a)] = x
+	d.n++
+}
+
+// PushFront adds x at the front of the deque.
+func (d *Deque[T]) PushFront(x T) {
+	d.growIfFull()
+	d.i = (d.i - 1 + len(d.a)) % len(d.a)
+	d.a[d.i] = x
+	d.n++
+}
+
+// PopFront removes and returns the element at the front of the deque.
+// Panics if the deque is empty.
+func (d *Deque[T]) PopFront() T {
+	if d.n == 0 {
+		panic("PopFront on an empty deque")
+	}
+	x := d.a[d.i]
+	var zero T
+	d.a[d.i] = zero // Remove element to allow GC.
+	d.i = (d.i + 1) % len(d.a)
+	d.n--
+	return x
+}
+
+// PopBack removes and returns the element at the back of the deque.
+// Panics if the deque is empty.
+func (d *Deque[T]) PopBack() T {
+	if d.n == 0 {
+		panic("PopBack on an empty deque")
+	}
+	j := (d.i + d.n - 1) % len(d.a)
+	x := d.a[j]
+	var zero T
+	d.a[j] = zero // Remove element to allow GC.
+	d.n--
+	return x
+}
+
+// Front returns the element at the front of the deque.
+// Panics if the deque is empty.
+func (d *Deque[T]) Front() T {
+	return d.At(0)
+}
+
+// Back returns the element at the back of the deque.
+// Panics if the deque is empty.
+func (d *Deque[T]) Back() T {
+	return d.At(d.n - 1)
+}
+
+// At returns the i'th element from the front of the deque.
+func (d *Deque[T]) At(i int) T {
+	checkIndex(i, d.n)
+	return d.a[(d.i+i)%len(d.a)]
+}
+
+// Set sets the i'th element from the front of the deque.
+func (d *Deque[T]) Set(i int, x T) {
+	checkIndex(i, d.n)
+	d.a[(d.i+i)%len(d.a)] = x
+}
+
+// Len returns the number of elements in the deque.
+func (d *Deque[T]) Len() int {
+	return d.n
+}
+
+// All iterates over the elements of the deque from front to back,
+// without modifying it.
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range d.n {
+			if !yield(i, d.a[(d.i+i)%len(d.a)]) {
+				return
+			}
+		}
+	}
+}
+
+// Doubles the underlying slice if it is full.
+func (d *Deque[T]) growIfFull() {
+	if d.n < len(d.a) {
+		return
+	}
+	a := make([]T, len(d.a)*2+1)
+	copy(a, d.a[d.i:])
+	copy(a[len(d.a)-d.i:], d.a[:d.i])
+	d.a = a
+	d.i = 0
+}
+
+// A Ring is a fixed-capacity FIFO buffer.
+type Ring[T any] struct {
+	a         []T
+	i, n      int
+	overwrite bool
+}
+
+// NewRing returns an empty ring buffer with the given capacity.
+// If overwrite is true, pushing to a full buffer drops its oldest element.
+// Otherwise, pushing to a full buffer fails.
+func NewRing[T any](capacity int, overwrite bool) *Ring[T] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("bad capacity: %d", capacity))
+	}
+	return &Ring[T]{a: make([]T, capacity), overwrite: overwrite}
+}
+
+// Push adds x at the back of the buffer.
+// Returns false if the buffer is full and does not overwrite,
+// in which case the buffer is unchanged.
+func (r *Ring[T]) Push(x T) bool {
+	if r.n == len(r.a) {
+		if !r.overwrite {
+			return false
+		}
+		r.a[r.i] = x
+		r.i = (r.i + 1) % len(r.a)
+		return true
+	}
+	r.a[(r.i+r.n)%len(r.a)] = x
+	r.n++
+	return true
+}
+
+// Pop removes and returns the oldest element in the buffer.
+// Panics if the buffer is empty.
+func (r *Ring[T]) Pop() T {
+	if r.n == 0 {
+		panic("Pop on an empty ring")
+	}
+	x := r.a[r.i]
+	var zero T
+	r.a[r.i] = zero // Remove element to allow GC.
+	r.i = (r.i + 1) % len(r.a)
+	r.n--
+	return x
+}
+
+// At returns the i'th oldest element in the buffer.
+func (r *Ring[T]) At(i int) T {
+	checkIndex(i, r.n)
+	return r.a[(r.i+i)%len(r.a)]
+}
+
+// Len returns the number of elements in the buffer.
+func (r *Ring[T]) Len() int {
+	return r.n
+}
+
+// Cap returns the capacity of the buffer.
+func (r *Ring[T]) Cap() int {
+	return len(r.a)
+}
+
+// Full returns whether the buffer is at full capacity.
+func (r *Ring[T]) Full() bool {
+	return r.n == len(r.a)
+}
+
+// All iterates over the elements of the buffer from oldest to newest,
+// without modifying it.
+func (r *Ring[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range r.n {
+			if !yield(i, r.a[(r.i+i)%len(r.a)]) {
+				return
+			}
+		}
+	}
+}
+
+// Panics if i is out of range [0,n).
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("index %d out of range [0,%d)", i, n))
+	}
+}
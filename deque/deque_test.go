@@ -0,0 +1,110 @@
+package deque
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	d := &Deque[int]{}
+	var want []int
+	for range 10000 {
+		switch rand.IntN(5) {
+		case 0:
+			x := rand.Int()
+			d.PushBack(x)
+			want = append(want, x)
+		case 1:
+			x := rand.Int()
+			d.PushFront(x)
+			want = slices.Insert(want, 0, x)
+		case 2:
+			if len(want) == 0 {
+				continue
+			}
+			if got := d.PopFront(); got != want[0] {
+				t.Fatalf("PopFront()=%v, want %v", got, want[0])
+			}
+			want = want[1:]
+		case 3:
+			if len(want) == 0 {
+				continue
+			}
+			if got := d.PopBack(); got != want[len(want)-1] {
+				t.Fatalf("PopBack()=%v, want %v", got, want[len(want)-1])
+			}
+			want = want[:len(want)-1]
+		case 4:
+			if len(want) == 0 {
+				continue
+			}
+			i := rand.IntN(len(want))
+			if got := d.At(i); got != want[i] {
+				t.Fatalf("At(%v)=%v, want %v", i, got, want[i])
+			}
+		}
+		if d.Len() != len(want) {
+			t.Fatalf("Len()=%v, want %v", d.Len(), len(want))
+		}
+	}
+	var got []int
+	for _, x := range d.All() {
+		got = append(got, x)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3, false)
+	for i := range 3 {
+		if !r.Push(i) {
+			t.Fatalf("Push(%v)=false, want true", i)
+		}
+	}
+	if r.Push(3) {
+		t.Fatalf("Push(3)=true, want false")
+	}
+	if got := r.Pop(); got != 0 {
+		t.Fatalf("Pop()=%v, want 0", got)
+	}
+	r.Push(3)
+	if got, want := ringSlice(r), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+}
+
+func TestRing_overwrite(t *testing.T) {
+	r := NewRing[int](3, true)
+	for i := range 5 {
+		if !r.Push(i) {
+			t.Fatalf("Push(%v)=false, want true", i)
+		}
+	}
+	if !r.Full() {
+		t.Fatalf("Full()=false, want true")
+	}
+	if got, want := ringSlice(r), []int{2, 3, 4}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+	if got := r.At(0); got != 2 {
+		t.Fatalf("At(0)=%v, want 2", got)
+	}
+	if got := r.Pop(); got != 2 {
+		t.Fatalf("Pop()=%v, want 2", got)
+	}
+	if got, want := ringSlice(r), []int{3, 4}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+}
+
+// Returns the elements of r in order.
+func ringSlice(r *Ring[int]) []int {
+	var result []int
+	for _, x := range r.All() {
+		result = append(result, x)
+	}
+	return result
+}
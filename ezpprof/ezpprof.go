@@ -17,10 +17,17 @@
 //	  ezpprof.Start("myfile.pprof")
 //	  defer ezpprof.Stop()
 //	}
+//
+// Or for a single function call:
+//
+//	ezpprof.Profile("myfile.pprof", func() {
+//	  {... some complicated code ...}
+//	})
 package ezpprof
 
 import (
 	"io"
+	"runtime"
 	"runtime/pprof"
 
 	"github.com/fluhus/gostuff/aio"
@@ -68,3 +75,20 @@ func Heap(file string) {
 		panic(err)
 	}
 }
+
+// SnapshotHeap runs a garbage collection and then writes heap profile to the
+// given file, so that the profile reflects the currently live objects.
+// Panics if an error occurs.
+func SnapshotHeap(file string) {
+	runtime.GC()
+	Heap(file)
+}
+
+// Profile runs fn while CPU profiling, and writes the profile to the
+// given file. Profiling stops even if fn panics.
+// Panics if an error occurs.
+func Profile(file string, fn func()) {
+	Start(file)
+	defer Stop()
+	fn()
+}
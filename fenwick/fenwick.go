@@ -0,0 +1,87 @@
+// Package fenwick provides a Fenwick tree (binary indexed tree).
+//
+// A Fenwick tree maintains prefix sums over an array of numbers,
+// with O(log n) point updates and prefix sum queries.
+package fenwick
+
+import (
+	"fmt"
+
+	"github.com/fluhus/gostuff/gnum"
+)
+
+// A Tree is a Fenwick tree over a fixed number of elements.
+type Tree[N gnum.Number] struct {
+	a []N // 1-based internal representation.
+}
+
+// New returns a tree with n elements, all zero.
+func New[N gnum.Number](n int) *Tree[N] {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	return &Tree[N]{make([]N, n+1)}
+}
+
+// FromSlice returns a tree whose elements are the values of s.
+// Runs in O(n).
+func FromSlice[S ~[]N, N gnum.Number](s S) *Tree[N] {
+	a := make([]N, len(s)+1)
+	copy(a[1:], s)
+	for i := 1; i < len(a); i++ {
+		if j := i + i&-i; j < len(a) {
+			a[j] += a[i]
+		}
+	}
+	return &Tree[N]{a}
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree[N]) Len() int {
+	return len(t.a) - 1
+}
+
+// Add adds delta to the i'th element.
+func (t *Tree[N]) Add(i int, delta N) {
+	t.checkIndex(i)
+	for i++; i < len(t.a); i += i & -i {
+		t.a[i] += delta
+	}
+}
+
+// Set sets the value of the i'th element.
+func (t *Tree[N]) Set(i int, v N) {
+	t.Add(i, v-t.Get(i))
+}
+
+// Get returns the value of the i'th element.
+func (t *Tree[N]) Get(i int) N {
+	return t.RangeSum(i, i+1)
+}
+
+// Sum returns the sum of the first n elements.
+func (t *Tree[N]) Sum(n int) N {
+	if n < 0 || n > t.Len() {
+		panic(fmt.Sprintf("n=%d out of range [0,%d]", n, t.Len()))
+	}
+	var s N
+	for ; n > 0; n -= n & -n {
+		s += t.a[n]
+	}
+	return s
+}
+
+// RangeSum returns the sum of elements i (inclusive) through j (exclusive).
+func (t *Tree[N]) RangeSum(i, j int) N {
+	if i > j {
+		panic(fmt.Sprintf("bad range: %d > %d", i, j))
+	}
+	return t.Sum(j) - t.Sum(i)
+}
+
+// Panics if i is not a valid element index.
+func (t *Tree[N]) checkIndex(i int) {
+	if i < 0 || i >= t.Len() {
+		panic(fmt.Sprintf("index %d out of range [0,%d)", i, t.Len()))
+	}
+}
@@ -0,0 +1,44 @@
+package fenwick
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fluhus/gostuff/gnum"
+)
+
+func TestFromSlice(t *testing.T) {
+	input := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	tr := FromSlice(input)
+	for i := range len(input) + 1 {
+		if got, want := tr.Sum(i), gnum.Sum(input[:i]); got != want {
+			t.Errorf("Sum(%v)=%v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	const n = 100
+	a := make([]float64, n)
+	tr := New[float64](n)
+	for range 1000 {
+		i := rand.IntN(n)
+		if rand.IntN(2) == 0 {
+			d := float64(rand.IntN(100) - 50)
+			a[i] += d
+			tr.Add(i, d)
+		} else {
+			v := float64(rand.IntN(100))
+			a[i] = v
+			tr.Set(i, v)
+		}
+		j := rand.IntN(n + 1)
+		k := j + rand.IntN(n-j+1)
+		if got, want := tr.RangeSum(j, k), gnum.Sum(a[j:k]); got != want {
+			t.Fatalf("RangeSum(%v,%v)=%v, want %v", j, k, got, want)
+		}
+		if got, want := tr.Get(i), a[i]; got != want {
+			t.Fatalf("Get(%v)=%v, want %v", i, got, want)
+		}
+	}
+}
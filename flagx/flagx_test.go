@@ -3,7 +3,9 @@ package flagx
 import (
 	"flag"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestRegexp(t *testing.T) {
@@ -126,3 +128,71 @@ func TestOneOf_int(t *testing.T) {
 		}()
 	}
 }
+
+func TestInts(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	ii := IntsFlagSet(fs, "i", []int{7}, "")
+	if want := []int{7}; !slices.Equal(*ii, want) {
+		t.Errorf("IntsFlagSet(...)=%v, want %v", *ii, want)
+	}
+	args := []string{"-i", "1,2", "-i", "3"}
+	fs.Parse(args)
+	if want := []int{1, 2, 3}; !slices.Equal(*ii, want) {
+		t.Errorf("Parse(%v)=%v, want %v", args, *ii, want)
+	}
+
+	func() {
+		args := []string{"-i", "1,a"}
+		defer func() {
+			recover()
+		}()
+		fs.Parse(args)
+		t.Errorf("Parse(%v)=%v, want error", args, *ii)
+	}()
+}
+
+func TestStrings(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	ss := StringsFlagSet(fs, "s", nil, "")
+	args := []string{"-s", "a, b", "-s", "c"}
+	fs.Parse(args)
+	if want := []string{"a", "b", "c"}; !slices.Equal(*ss, want) {
+		t.Errorf("Parse(%v)=%v, want %v", args, *ss, want)
+	}
+}
+
+func TestDurations(t *testing.T) {
+	fs := flag.NewFlagSet("", flag.PanicOnError)
+	dd := DurationsFlagSet(fs, "d", nil, "")
+	args := []string{"-d", "1s,2m"}
+	fs.Parse(args)
+	if want := []time.Duration{time.Second, 2 * time.Minute}; !slices.Equal(
+		*dd, want) {
+		t.Errorf("Parse(%v)=%v, want %v", args, *dd, want)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"0", 0}, {"512", 512}, {"512B", 512}, {"1k", 1024},
+		{"1.5K", 1536}, {"512M", 512 << 20}, {"2GB", 2 << 30},
+		{"3GiB", 3 << 30}, {"1T", 1 << 40},
+	}
+	for _, test := range tests {
+		got, err := ParseByteSize(test.input)
+		if err != nil {
+			t.Fatalf("ParseByteSize(%q) failed: %v", test.input, err)
+		}
+		if got != test.want {
+			t.Errorf("ParseByteSize(%q)=%v, want %v", test.input, got, test.want)
+		}
+	}
+	for _, input := range []string{"", "M", "1X", "-1K", "1..5", "1E"} {
+		if got, err := ParseByteSize(input); err == nil {
+			t.Errorf("ParseByteSize(%q)=%v, want error", input, got)
+		}
+	}
+}
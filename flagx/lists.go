@@ -0,0 +1,208 @@
+package flagx
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defines a comma-separated list flag, parsing each element with parse.
+// The flag may be repeated, appending to the list. The first occurrence
+// replaces the default value.
+func listFlagSet[T any](fs *flag.FlagSet, name string, value []T,
+	usage string, parse func(string) (T, error)) *[]T {
+	p := &value
+	set := false
+	fs.Func(name, usage, func(s string) error {
+		if !set {
+			*p = nil
+			set = true
+		}
+		for _, part := range strings.Split(s, ",") {
+			x, err := parse(strings.TrimSpace(part))
+			if err != nil {
+				return err
+			}
+			*p = append(*p, x)
+		}
+		return nil
+	})
+	return p
+}
+
+// StringsFlagSet defines a comma-separated string list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func StringsFlagSet(fs *flag.FlagSet, name string, value []string,
+	usage string) *[]string {
+	return listFlagSet(fs, name, value, usage, func(s string) (string, error) {
+		return s, nil
+	})
+}
+
+// Strings defines a comma-separated string list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func Strings(name string, value []string, usage string) *[]string {
+	return StringsFlagSet(flag.CommandLine, name, value, usage)
+}
+
+// IntsFlagSet defines a comma-separated int list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func IntsFlagSet(fs *flag.FlagSet, name string, value []int,
+	usage string) *[]int {
+	return listFlagSet(fs, name, value, usage, strconv.Atoi)
+}
+
+// Ints defines a comma-separated int list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func Ints(name string, value []int, usage string) *[]int {
+	return IntsFlagSet(flag.CommandLine, name, value, usage)
+}
+
+// FloatsFlagSet defines a comma-separated float list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func FloatsFlagSet(fs *flag.FlagSet, name string, value []float64,
+	usage string) *[]float64 {
+	return listFlagSet(fs, name, value, usage, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Floats defines a comma-separated float list flag with specified
+// name, default value, and usage string.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func Floats(name string, value []float64, usage string) *[]float64 {
+	return FloatsFlagSet(flag.CommandLine, name, value, usage)
+}
+
+// DurationsFlagSet defines a comma-separated duration list flag with
+// specified name, default value, and usage string.
+// Elements are parsed with [time.ParseDuration].
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func DurationsFlagSet(fs *flag.FlagSet, name string, value []time.Duration,
+	usage string) *[]time.Duration {
+	return listFlagSet(fs, name, value, usage, time.ParseDuration)
+}
+
+// Durations defines a comma-separated duration list flag with
+// specified name, default value, and usage string.
+// Elements are parsed with [time.ParseDuration].
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func Durations(name string, value []time.Duration,
+	usage string) *[]time.Duration {
+	return DurationsFlagSet(flag.CommandLine, name, value, usage)
+}
+
+// TimesFlagSet defines a comma-separated time list flag with specified
+// name, default value, layout and usage string.
+// Elements are parsed with [time.Parse] using the given layout.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func TimesFlagSet(fs *flag.FlagSet, name string, value []time.Time,
+	layout string, usage string) *[]time.Time {
+	return listFlagSet(fs, name, value, usage, func(s string) (time.Time, error) {
+		return time.Parse(layout, s)
+	})
+}
+
+// Times defines a comma-separated time list flag with specified
+// name, default value, layout and usage string.
+// Elements are parsed with [time.Parse] using the given layout.
+// The flag may be repeated, appending to the list.
+// The return value is the address of a slice that stores the value of the
+// flag.
+func Times(name string, value []time.Time, layout string,
+	usage string) *[]time.Time {
+	return TimesFlagSet(flag.CommandLine, name, value, layout, usage)
+}
+
+// ByteSizeFlagSet defines a byte-size flag with specified name,
+// default value, and usage string.
+// Values are parsed with [ParseByteSize].
+// The return value is the address of an int64 variable that
+// stores the value of the flag.
+func ByteSizeFlagSet(fs *flag.FlagSet, name string, value int64,
+	usage string) *int64 {
+	p := &value
+	fs.Func(name, usage, func(s string) error {
+		n, err := ParseByteSize(s)
+		if err != nil {
+			return err
+		}
+		*p = n
+		return nil
+	})
+	return p
+}
+
+// ByteSize defines a byte-size flag with specified name,
+// default value, and usage string.
+// Values are parsed with [ParseByteSize].
+// The return value is the address of an int64 variable that
+// stores the value of the flag.
+func ByteSize(name string, value int64, usage string) *int64 {
+	return ByteSizeFlagSet(flag.CommandLine, name, value, usage)
+}
+
+// Multipliers of byte-size suffixes.
+var byteSuffixes = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+// ParseByteSize parses a number of bytes with an optional binary suffix,
+// like "512", "1.5K", "512M" or "2GB". Suffixes are case insensitive and
+// powers of 1024; a trailing "B" or "iB" is optional.
+func ParseByteSize(s string) (int64, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	if v, ok := strings.CutSuffix(u, "IB"); ok {
+		u = v
+	} else if v, ok := strings.CutSuffix(u, "B"); ok {
+		u = v
+	}
+	i := len(u)
+	for i > 0 && (u[i-1] < '0' || u[i-1] > '9') && u[i-1] != '.' {
+		i--
+	}
+	m, ok := byteSuffixes[u[i:]]
+	if !ok {
+		return 0, fmt.Errorf("bad byte size: %q", s)
+	}
+	f, err := strconv.ParseFloat(u[:i], 64)
+	if err != nil || f < 0 || math.IsNaN(f) {
+		return 0, fmt.Errorf("bad byte size: %q", s)
+	}
+	f *= m
+	if f >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size too large: %q", s)
+	}
+	return int64(f), nil
+}
@@ -0,0 +1,96 @@
+package gnum
+
+import "fmt"
+
+// Transpose returns the transpose of the rectangular matrix m.
+func Transpose[S ~[]N, N any](m []S) []S {
+	cols := assertRectangular(m)
+	result := make([]S, cols)
+	for j := range result {
+		result[j] = make(S, len(m))
+		for i := range m {
+			result[j][i] = m[i][j]
+		}
+	}
+	return result
+}
+
+// Flatten returns the rows of m concatenated into a single slice.
+func Flatten[S ~[]N, N any](m []S) S {
+	n := 0
+	for _, row := range m {
+		n += len(row)
+	}
+	result := make(S, 0, n)
+	for _, row := range m {
+		result = append(result, row...)
+	}
+	return result
+}
+
+// Reshape splits a into rows of length cols. The rows share a's memory.
+// Panics if the length of a is not divisible by cols.
+func Reshape[S ~[]N, N any](a S, cols int) []S {
+	if cols < 1 || len(a)%cols != 0 {
+		panic(fmt.Sprintf("bad number of columns: %d for length %d",
+			cols, len(a)))
+	}
+	result := make([]S, len(a)/cols)
+	for i := range result {
+		result[i] = a[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return result
+}
+
+// RowSums returns the sum of each row of m.
+func RowSums[S ~[]N, N Number](m []S) S {
+	result := make(S, len(m))
+	for i, row := range m {
+		result[i] = Sum(row)
+	}
+	return result
+}
+
+// ColSums returns the sum of each column of the rectangular matrix m.
+func ColSums[S ~[]N, N Number](m []S) S {
+	result := make(S, assertRectangular(m))
+	for _, row := range m {
+		for j, x := range row {
+			result[j] += x
+		}
+	}
+	return result
+}
+
+// Fill2D sets all the elements of m to x.
+func Fill2D[S ~[]N, N any](m []S, x N) {
+	for _, row := range m {
+		for j := range row {
+			row[j] = x
+		}
+	}
+}
+
+// Copy2D returns a deep copy of m.
+func Copy2D[S ~[]N, N any](m []S) []S {
+	result := make([]S, len(m))
+	for i, row := range m {
+		result[i] = Copy(row)
+	}
+	return result
+}
+
+// Panics if the rows of m are of different lengths.
+// Returns the number of columns.
+func assertRectangular[S ~[]N, N any](m []S) int {
+	if len(m) == 0 {
+		return 0
+	}
+	for i, row := range m {
+		if len(row) != len(m[0]) {
+			panic(fmt.Sprintf("mismatching row lengths: row 0 has %d, "+
+				"row %d has %d", len(m[0]), i, len(row)))
+		}
+	}
+	return len(m[0])
+}
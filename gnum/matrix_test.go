@@ -0,0 +1,63 @@
+package gnum
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTranspose(t *testing.T) {
+	m := [][]int{{1, 2, 3}, {4, 5, 6}}
+	want := [][]int{{1, 4}, {2, 5}, {3, 6}}
+	if got := Transpose(m); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Transpose(%v)=%v, want %v", m, got, want)
+	}
+	if got := Transpose([][]int{}); len(got) != 0 {
+		t.Errorf("Transpose([])=%v, want []", got)
+	}
+}
+
+func TestTransposeRagged(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Transpose(ragged) did not panic")
+		}
+	}()
+	Transpose([][]int{{1, 2}, {3}})
+}
+
+func TestFlattenReshape(t *testing.T) {
+	m := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+	flat := Flatten(m)
+	if want := []float64{1, 2, 3, 4, 5, 6}; !slices.Equal(flat, want) {
+		t.Fatalf("Flatten(%v)=%v, want %v", m, flat, want)
+	}
+	if got := Reshape(flat, 2); !slices.EqualFunc(got, m, slices.Equal) {
+		t.Fatalf("Reshape(%v,2)=%v, want %v", flat, got, m)
+	}
+	want := [][]float64{{1, 2, 3}, {4, 5, 6}}
+	if got := Reshape(flat, 3); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Reshape(%v,3)=%v, want %v", flat, got, want)
+	}
+}
+
+func TestSums(t *testing.T) {
+	m := [][]int{{1, 2, 3}, {4, 5, 6}}
+	if got, want := RowSums(m), []int{6, 15}; !slices.Equal(got, want) {
+		t.Errorf("RowSums(%v)=%v, want %v", m, got, want)
+	}
+	if got, want := ColSums(m), []int{5, 7, 9}; !slices.Equal(got, want) {
+		t.Errorf("ColSums(%v)=%v, want %v", m, got, want)
+	}
+}
+
+func TestFillCopy2D(t *testing.T) {
+	m := [][]int{{1, 2}, {3}}
+	c := Copy2D(m)
+	Fill2D(m, 7)
+	if want := [][]int{{7, 7}, {7}}; !slices.EqualFunc(m, want, slices.Equal) {
+		t.Errorf("Fill2D(...)=%v, want %v", m, want)
+	}
+	if want := [][]int{{1, 2}, {3}}; !slices.EqualFunc(c, want, slices.Equal) {
+		t.Errorf("Copy2D(...)=%v, want %v", c, want)
+	}
+}
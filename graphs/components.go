@@ -0,0 +1,197 @@
+package graphs
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/fluhus/gostuff/heaps"
+	"github.com/fluhus/gostuff/snm"
+)
+
+// Kruskal returns the edges of a minimum spanning forest of the graph,
+// using Kruskal's algorithm.
+// Panics if the graph is directed.
+func (g *Weighted[T]) Kruskal() []Edge[T] {
+	if g.directed {
+		panic("Kruskal called on a directed graph")
+	}
+	type iedge struct {
+		a, b int
+		w    float64
+	}
+	var edges []iedge
+	for i, adj := range g.adj {
+		for _, e := range adj {
+			if e.to > i {
+				edges = append(edges, iedge{i, e.to, e.w})
+			}
+		}
+	}
+	slices.SortStableFunc(edges, func(a, b iedge) int {
+		return cmp.Compare(a.w, b.w)
+	})
+
+	elems := g.v.Elements()
+	u := NewUnionFind(len(g.v))
+	var result []Edge[T]
+	for _, e := range edges {
+		if u.Union(e.a, e.b) {
+			result = append(result, Edge[T]{elems[e.a], elems[e.b], e.w})
+		}
+	}
+	return result
+}
+
+// Prim returns the edges of a minimum spanning forest of the graph,
+// using Prim's algorithm.
+// Panics if the graph is directed.
+func (g *Weighted[T]) Prim() []Edge[T] {
+	if g.directed {
+		panic("Prim called on a directed graph")
+	}
+	type item struct {
+		from, to int
+		w        float64
+	}
+	elems := g.v.Elements()
+	done := make([]bool, len(g.v))
+	best := snm.Slice(len(g.v), func(int) float64 { return math.Inf(1) })
+	q := heaps.New(func(a, b item) bool { return a.w < b.w })
+	var result []Edge[T]
+
+	for root := range g.adj {
+		if done[root] {
+			continue
+		}
+		q.Push(item{-1, root, 0})
+		for q.Len() > 0 {
+			x := q.Pop()
+			if done[x.to] {
+				continue
+			}
+			done[x.to] = true
+			if x.from != -1 {
+				result = append(result,
+					Edge[T]{elems[x.from], elems[x.to], x.w})
+			}
+			for _, e := range g.adj[x.to] {
+				if !done[e.to] && e.w < best[e.to] {
+					best[e.to] = e.w
+					q.Push(item{x.to, e.to, e.w})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// ConnectedComponents returns a slice of connected components.
+// In a directed graph, edge directions are ignored (weakly connected
+// components).
+// In each component, the elements are ordered by order of addition to the
+// graph.
+// The components are ordered by the order of addition of their
+// first elements.
+func (g *Weighted[T]) ConnectedComponents() [][]T {
+	u := NewUnionFind(len(g.v))
+	for i, adj := range g.adj {
+		for _, e := range adj {
+			u.Union(i, e.to)
+		}
+	}
+	comps := map[int][]int{}
+	for i := range g.adj {
+		root := u.Find(i)
+		comps[root] = append(comps[root], i)
+	}
+	return g.componentValues(slices.Collect(maps.Values(comps)))
+}
+
+// StronglyConnectedComponents returns a slice of strongly connected
+// components, using Tarjan's algorithm.
+// In each component, the elements are ordered by order of addition to the
+// graph.
+// The components are ordered by the order of addition of their
+// first elements.
+// Panics if the graph is undirected.
+func (g *Weighted[T]) StronglyConnectedComponents() [][]T {
+	if !g.directed {
+		panic("StronglyConnectedComponents called on an undirected graph")
+	}
+	type frame struct {
+		v, i int // Vertex and index of next edge to explore.
+	}
+	n := len(g.v)
+	idx := snm.Slice(n, func(int) int { return -1 })
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	var stack []int
+	var comps [][]int
+	counter := 0
+
+	visit := func(v int) {
+		idx[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+	}
+
+	for root := range n {
+		if idx[root] != -1 {
+			continue
+		}
+		visit(root)
+		calls := []frame{{root, 0}}
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			if f.i < len(g.adj[f.v]) {
+				w := g.adj[f.v][f.i].to
+				f.i++
+				if idx[w] == -1 {
+					visit(w)
+					calls = append(calls, frame{w, 0})
+				} else if onStack[w] {
+					low[f.v] = min(low[f.v], idx[w])
+				}
+				continue
+			}
+			// Done with v.
+			v := f.v
+			calls = calls[:len(calls)-1]
+			if low[v] == idx[v] {
+				var comp []int
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp = append(comp, w)
+					if w == v {
+						break
+					}
+				}
+				comps = append(comps, comp)
+			}
+			if len(calls) > 0 {
+				p := calls[len(calls)-1].v
+				low[p] = min(low[p], low[v])
+			}
+		}
+	}
+	return g.componentValues(comps)
+}
+
+// Sorts the given components of vertex IDs and converts them to values.
+func (g *Weighted[T]) componentValues(comps [][]int) [][]T {
+	for _, c := range comps {
+		slices.Sort(c)
+	}
+	slices.SortFunc(comps, func(a, b []int) int {
+		return cmp.Compare(a[0], b[0])
+	})
+	elems := g.v.Elements()
+	return snm.SliceToSlice(comps, func(c []int) []T {
+		return snm.At(elems, c)
+	})
+}
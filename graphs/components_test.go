@@ -0,0 +1,111 @@
+package graphs
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	u := NewUnionFind(6)
+	if !u.Union(0, 1) || !u.Union(2, 3) || !u.Union(1, 3) {
+		t.Fatalf("Union(...)=false, want true")
+	}
+	if u.Union(0, 2) {
+		t.Fatalf("Union(0,2)=true, want false")
+	}
+	if !u.Same(0, 3) || u.Same(0, 4) {
+		t.Fatalf("Same(...) returned wrong results")
+	}
+	if u.Size(2) != 4 || u.Size(5) != 1 {
+		t.Fatalf("Size(2),Size(5)=%v,%v, want 4,1", u.Size(2), u.Size(5))
+	}
+}
+
+func TestMinSpanningTree(t *testing.T) {
+	g := NewUndirected[string]()
+	g.AddEdge("a", "b", 4)
+	g.AddEdge("a", "h", 8)
+	g.AddEdge("b", "c", 8)
+	g.AddEdge("b", "h", 11)
+	g.AddEdge("c", "d", 7)
+	g.AddEdge("c", "f", 4)
+	g.AddEdge("c", "i", 2)
+	g.AddEdge("d", "e", 9)
+	g.AddEdge("d", "f", 14)
+	g.AddEdge("e", "f", 10)
+	g.AddEdge("f", "g", 2)
+	g.AddEdge("g", "h", 1)
+	g.AddEdge("g", "i", 6)
+	g.AddEdge("h", "i", 7)
+	g.AddEdge("x", "y", 3)
+
+	for name, f := range map[string]func() []Edge[string]{
+		"Kruskal": g.Kruskal, "Prim": g.Prim,
+	} {
+		got := f()
+		if len(got) != 9 {
+			t.Errorf("%s() len=%v, want 9", name, len(got))
+		}
+		sum := 0.0
+		for _, e := range got {
+			sum += e.Weight
+		}
+		if sum != 40 {
+			t.Errorf("%s() weight=%v, want 40", name, sum)
+		}
+	}
+}
+
+func TestMinSpanningTree_random(t *testing.T) {
+	g := NewUndirected[int]()
+	for range 300 {
+		g.AddEdge(rand.IntN(50), rand.IntN(50), float64(rand.IntN(20)))
+	}
+	sum := func(edges []Edge[int]) float64 {
+		s := 0.0
+		for _, e := range edges {
+			s += e.Weight
+		}
+		return s
+	}
+	k, p := g.Kruskal(), g.Prim()
+	if len(k) != len(p) || sum(k) != sum(p) {
+		t.Fatalf("Kruskal()=%v,%v, Prim()=%v,%v, want equal",
+			len(k), sum(k), len(p), sum(p))
+	}
+}
+
+func TestWeightedComponents(t *testing.T) {
+	g := NewDirected[int]()
+	for i := range 8 {
+		g.AddVertices(i)
+	}
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(2, 1, 1)
+	g.AddEdge(4, 5, 1)
+	g.AddEdge(7, 5, 1)
+	want := [][]int{{0, 1, 2}, {3}, {4, 5, 7}, {6}}
+	if got := g.ConnectedComponents(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ConnectedComponents()=%v, want %v", got, want)
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("c", "a", 1)
+	g.AddEdge("b", "d", 1)
+	g.AddEdge("d", "e", 1)
+	g.AddEdge("e", "f", 1)
+	g.AddEdge("f", "d", 1)
+	g.AddEdge("g", "f", 1)
+	g.AddEdge("g", "h", 1)
+	g.AddEdge("h", "g", 1)
+	g.AddVertices("i")
+	want := [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h"}, {"i"}}
+	if got := g.StronglyConnectedComponents(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("StronglyConnectedComponents()=%v, want %v", got, want)
+	}
+}
@@ -1,4 +1,4 @@
-// Package graphs implements a simple graph.
+// Package graphs implements simple graphs and graph algorithms.
 package graphs
 
 import (
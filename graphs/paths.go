@@ -0,0 +1,103 @@
+package graphs
+
+import (
+	"math"
+	"slices"
+
+	"github.com/fluhus/gostuff/heaps"
+	"github.com/fluhus/gostuff/snm"
+)
+
+// ShortestPath returns the lightest path from a to b, including both,
+// and its total weight, using Dijkstra's algorithm.
+// Returns false if b is not reachable from a.
+// Panics if a negative edge weight is encountered.
+func (g *Weighted[T]) ShortestPath(a, b T) ([]T, float64, bool) {
+	return g.AStar(a, b, nil)
+}
+
+// AStar returns the lightest path from a to b, including both,
+// and its total weight, using the A* algorithm.
+// h estimates the remaining weight from a vertex to b. For the result to be
+// optimal, h must be consistent: it never overestimates, and h(u) is at most
+// the weight of an edge from u to v plus h(v).
+// A nil h is equivalent to Dijkstra's algorithm.
+// Returns false if b is not reachable from a.
+// Panics if a negative edge weight is encountered.
+func (g *Weighted[T]) AStar(a, b T, h func(T) float64) ([]T, float64, bool) {
+	ia, oka := g.v[a]
+	ib, okb := g.v[b]
+	if !oka || !okb {
+		return nil, 0, false
+	}
+	elems := g.v.Elements()
+	hh := func(int) float64 { return 0 }
+	if h != nil {
+		hh = func(i int) float64 { return h(elems[i]) }
+	}
+	dist, prev := g.dijkstra(ia, ib, hh)
+	if math.IsInf(dist[ib], 1) {
+		return nil, 0, false
+	}
+	var path []int
+	for i := ib; i != -1; i = prev[i] {
+		path = append(path, i)
+	}
+	slices.Reverse(path)
+	return snm.At(elems, path), dist[ib], true
+}
+
+// ShortestPaths returns the weights of the lightest paths from a to
+// every vertex that is reachable from it, using Dijkstra's algorithm.
+// Panics if a negative edge weight is encountered.
+func (g *Weighted[T]) ShortestPaths(a T) map[T]float64 {
+	ia, ok := g.v[a]
+	if !ok {
+		return map[T]float64{}
+	}
+	dist, _ := g.dijkstra(ia, -1, func(int) float64 { return 0 })
+	elems := g.v.Elements()
+	result := map[T]float64{}
+	for i, d := range dist {
+		if !math.IsInf(d, 1) {
+			result[elems[i]] = d
+		}
+	}
+	return result
+}
+
+// Runs A* from a, stopping when b is reached (b=-1 for no stopping).
+// Returns the distance of each vertex from a and the previous vertex on
+// its lightest path.
+func (g *Weighted[T]) dijkstra(a, b int, h func(int) float64) (
+	[]float64, []int) {
+	type item struct {
+		v int
+		f float64 // Distance + heuristic.
+	}
+	dist := snm.Slice(len(g.v), func(int) float64 { return math.Inf(1) })
+	prev := snm.Slice(len(g.v), func(int) int { return -1 })
+	done := make([]bool, len(g.v))
+	dist[a] = 0
+	q := heaps.New(func(x, y item) bool { return x.f < y.f })
+	q.Push(item{a, h(a)})
+	for q.Len() > 0 {
+		v := q.Pop().v
+		if done[v] {
+			continue
+		}
+		done[v] = true
+		if v == b {
+			break
+		}
+		for _, e := range g.adj[v] {
+			checkWeight(e.w)
+			if d := dist[v] + e.w; d < dist[e.to] {
+				dist[e.to] = d
+				prev[e.to] = v
+				q.Push(item{e.to, d + h(e.to)})
+			}
+		}
+	}
+	return dist, prev
+}
@@ -0,0 +1,57 @@
+package graphs
+
+// UnionFind is a disjoint-set structure over the integers 0..n-1,
+// with path compression and union by size.
+type UnionFind struct {
+	p []int // Parent of each element.
+	s []int // Size of each root's set.
+}
+
+// NewUnionFind returns a structure where each of the integers 0..n-1
+// is in its own set.
+func NewUnionFind(n int) *UnionFind {
+	p := make([]int, n)
+	s := make([]int, n)
+	for i := range p {
+		p[i] = i
+		s[i] = 1
+	}
+	return &UnionFind{p, s}
+}
+
+// Find returns the representative of i's set.
+func (u *UnionFind) Find(i int) int {
+	root := i
+	for u.p[root] != root {
+		root = u.p[root]
+	}
+	for u.p[i] != root { // Path compression.
+		u.p[i], i = root, u.p[i]
+	}
+	return root
+}
+
+// Union merges the sets of i and j.
+// Returns false if they were already in the same set.
+func (u *UnionFind) Union(i, j int) bool {
+	i, j = u.Find(i), u.Find(j)
+	if i == j {
+		return false
+	}
+	if u.s[i] < u.s[j] {
+		i, j = j, i
+	}
+	u.p[j] = i
+	u.s[i] += u.s[j]
+	return true
+}
+
+// Same returns whether i and j are in the same set.
+func (u *UnionFind) Same(i, j int) bool {
+	return u.Find(i) == u.Find(j)
+}
+
+// Size returns the size of i's set.
+func (u *UnionFind) Size(i int) int {
+	return u.s[u.Find(i)]
+}
@@ -0,0 +1,346 @@
+package graphs
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/fluhus/gostuff/sets"
+	"github.com/fluhus/gostuff/snm"
+)
+
+// Weighted is a graph with weighted edges, stored as adjacency lists.
+// It may be directed or undirected.
+// Unweighted graphs can use a weight of 1 for all edges.
+type Weighted[T comparable] struct {
+	v        snm.Enumerator[T] // Value to ID.
+	adj      [][]wedge         // Outgoing edges of each vertex.
+	directed bool
+	ne       int // Number of edges.
+}
+
+// An outgoing edge in an adjacency list.
+type wedge struct {
+	to int
+	w  float64
+}
+
+// An Edge is a weighted edge between two vertices.
+type Edge[T any] struct {
+	From   T
+	To     T
+	Weight float64
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected[T comparable]() *Weighted[T] {
+	return &Weighted[T]{v: snm.Enumerator[T]{}, directed: true}
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected[T comparable]() *Weighted[T] {
+	return &Weighted[T]{v: snm.Enumerator[T]{}, directed: false}
+}
+
+// Directed returns whether this graph is directed.
+func (g *Weighted[T]) Directed() bool {
+	return g.directed
+}
+
+// AddVertices adds the given values as vertices.
+// Values that already exist are ignored.
+func (g *Weighted[T]) AddVertices(t ...T) {
+	for _, v := range t {
+		g.id(v)
+	}
+}
+
+// NumVertices returns the current number of vertices.
+func (g *Weighted[T]) NumVertices() int {
+	return len(g.v)
+}
+
+// NumEdges returns the current number of edges.
+func (g *Weighted[T]) NumEdges() int {
+	return g.ne
+}
+
+// Vertices iterates over current set of vertices,
+// by order of addition to the graph.
+func (g *Weighted[T]) Vertices() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range g.v.Elements() {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Edges iterates over current set of edges.
+// In an undirected graph, each edge is yielded once.
+func (g *Weighted[T]) Edges() iter.Seq[Edge[T]] {
+	return func(yield func(Edge[T]) bool) {
+		flat := g.v.Elements()
+		for i, edges := range g.adj {
+			for _, e := range edges {
+				if !g.directed && e.to < i {
+					continue
+				}
+				if !yield(Edge[T]{flat[i], flat[e.to], e.w}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Neighbors iterates over the vertices that v has an edge to,
+// along with the edges' weights.
+func (g *Weighted[T]) Neighbors(v T) iter.Seq2[T, float64] {
+	return func(yield func(T, float64) bool) {
+		i, ok := g.v[v]
+		if !ok {
+			return
+		}
+		flat := g.v.Elements()
+		for _, e := range g.adj[i] {
+			if !yield(flat[e.to], e.w) {
+				return
+			}
+		}
+	}
+}
+
+// AddEdge adds a and b to the vertex set and adds an edge from a to b
+// with weight w.
+// In an undirected graph, AddEdge(a,b,w) is equivalent to AddEdge(b,a,w).
+// If the edge already exists, its weight is replaced.
+func (g *Weighted[T]) AddEdge(a, b T, w float64) {
+	ia, ib := g.id(a), g.id(b)
+	if !g.setEdge(ia, ib, w) {
+		g.ne++
+	}
+	if !g.directed && ia != ib {
+		g.setEdge(ib, ia, w)
+	}
+}
+
+// DeleteEdge removes the edge from a to b,
+// while keeping them in the vertex set.
+// Returns whether the edge existed.
+func (g *Weighted[T]) DeleteEdge(a, b T) bool {
+	ia, oka := g.v[a]
+	ib, okb := g.v[b]
+	if !oka || !okb {
+		return false
+	}
+	if !g.deleteEdge(ia, ib) {
+		return false
+	}
+	if !g.directed && ia != ib {
+		g.deleteEdge(ib, ia)
+	}
+	g.ne--
+	return true
+}
+
+// Weight returns the weight of the edge from a to b,
+// and whether that edge exists.
+func (g *Weighted[T]) Weight(a, b T) (float64, bool) {
+	ia, oka := g.v[a]
+	ib, okb := g.v[b]
+	if !oka || !okb {
+		return 0, false
+	}
+	for _, e := range g.adj[ia] {
+		if e.to == ib {
+			return e.w, true
+		}
+	}
+	return 0, false
+}
+
+// HasEdge returns whether there is an edge from a to b.
+func (g *Weighted[T]) HasEdge(a, b T) bool {
+	_, ok := g.Weight(a, b)
+	return ok
+}
+
+// BFS iterates over the vertices reachable from start in a breadth-first
+// ordering, including start.
+func (g *Weighted[T]) BFS(start T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		istart, ok := g.v[start]
+		if !ok {
+			return
+		}
+		elems := g.v.Elements()
+		done := sets.Set[int]{}.Add(istart)
+		q := &snm.Queue[int]{}
+		q.Enqueue(istart)
+		for v := range q.Seq() {
+			if !yield(elems[v]) {
+				return
+			}
+			for _, e := range g.adj[v] {
+				if done.Has(e.to) {
+					continue
+				}
+				done.Add(e.to)
+				q.Enqueue(e.to)
+			}
+		}
+	}
+}
+
+// DFS iterates over the vertices reachable from start in a depth-first
+// ordering, including start.
+func (g *Weighted[T]) DFS(start T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		istart, ok := g.v[start]
+		if !ok {
+			return
+		}
+		elems := g.v.Elements()
+		done := sets.Set[int]{}
+		stack := []int{istart}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if done.Has(v) {
+				continue
+			}
+			done.Add(v)
+			if !yield(elems[v]) {
+				return
+			}
+			// Push in reverse so that neighbors are visited by order
+			// of addition.
+			for _, e := range slices.Backward(g.adj[v]) {
+				if !done.Has(e.to) {
+					stack = append(stack, e.to)
+				}
+			}
+		}
+	}
+}
+
+// TopologicalSort returns the vertices ordered such that for every edge
+// from a to b, a comes before b.
+// Returns false if the graph has a cycle, in which case there is no such
+// ordering.
+// Panics if the graph is undirected.
+func (g *Weighted[T]) TopologicalSort() ([]T, bool) {
+	if !g.directed {
+		panic("TopologicalSort called on an undirected graph")
+	}
+	order := g.topoSort()
+	if len(order) != len(g.v) {
+		return nil, false
+	}
+	elems := g.v.Elements()
+	return snm.At(elems, order), true
+}
+
+// HasCycle returns whether the graph contains a cycle.
+// In an undirected graph, a single edge does not count as a cycle,
+// but a self-loop does.
+func (g *Weighted[T]) HasCycle() bool {
+	if g.directed {
+		return len(g.topoSort()) != len(g.v)
+	}
+	// Undirected: DFS while tracking each vertex's parent.
+	parent := snm.Slice(len(g.v), func(int) int { return -2 })
+	for root := range g.adj {
+		if parent[root] != -2 {
+			continue
+		}
+		parent[root] = -1
+		stack := []int{root}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range g.adj[v] {
+				if e.to == v {
+					return true // Self-loop.
+				}
+				if parent[e.to] == -2 {
+					parent[e.to] = v
+					stack = append(stack, e.to)
+				} else if e.to != parent[v] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Returns vertex IDs in topological order using Kahn's algorithm.
+// If the graph has a cycle, the result is shorter than the number of
+// vertices.
+func (g *Weighted[T]) topoSort() []int {
+	indeg := make([]int, len(g.v))
+	for _, edges := range g.adj {
+		for _, e := range edges {
+			indeg[e.to]++
+		}
+	}
+	q := &snm.Queue[int]{}
+	for i, d := range indeg {
+		if d == 0 {
+			q.Enqueue(i)
+		}
+	}
+	var order []int
+	for v := range q.Seq() {
+		order = append(order, v)
+		for _, e := range g.adj[v] {
+			indeg[e.to]--
+			if indeg[e.to] == 0 {
+				q.Enqueue(e.to)
+			}
+		}
+	}
+	return order
+}
+
+// Returns the ID of v, adding it if needed.
+func (g *Weighted[T]) id(v T) int {
+	i := g.v.IndexOf(v)
+	if i == len(g.adj) {
+		g.adj = append(g.adj, nil)
+	}
+	return i
+}
+
+// Sets the weight of the edge from a to b.
+// Returns whether the edge existed.
+func (g *Weighted[T]) setEdge(a, b int, w float64) bool {
+	for i := range g.adj[a] {
+		if g.adj[a][i].to == b {
+			g.adj[a][i].w = w
+			return true
+		}
+	}
+	g.adj[a] = append(g.adj[a], wedge{b, w})
+	return false
+}
+
+// Removes the edge from a to b. Returns whether the edge existed.
+func (g *Weighted[T]) deleteEdge(a, b int) bool {
+	i := slices.IndexFunc(g.adj[a], func(e wedge) bool { return e.to == b })
+	if i == -1 {
+		return false
+	}
+	g.adj[a] = slices.Delete(g.adj[a], i, i+1)
+	return true
+}
+
+// Panics if w is negative.
+func checkWeight(w float64) {
+	if w < 0 {
+		panic(fmt.Sprintf("negative edge weight: %v", w))
+	}
+}
@@ -0,0 +1,171 @@
+package graphs
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestWeighted_edges(t *testing.T) {
+	g := NewUndirected[string]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "a", 2)
+	g.AddEdge("b", "c", 3)
+	g.AddVertices("d")
+	if g.NumVertices() != 4 {
+		t.Fatalf("NumVertices()=%v, want 4", g.NumVertices())
+	}
+	if g.NumEdges() != 2 {
+		t.Fatalf("NumEdges()=%v, want 2", g.NumEdges())
+	}
+	if w, ok := g.Weight("a", "b"); !ok || w != 2 {
+		t.Fatalf("Weight(a,b)=%v,%v, want 2,true", w, ok)
+	}
+	if !g.DeleteEdge("c", "b") {
+		t.Fatalf("DeleteEdge(c,b)=false, want true")
+	}
+	if g.HasEdge("b", "c") {
+		t.Fatalf("HasEdge(b,c)=true, want false")
+	}
+	got := slices.Collect(g.Edges())
+	if want := []Edge[string]{{"a", "b", 2}}; !slices.Equal(got, want) {
+		t.Fatalf("Edges()=%v, want %v", got, want)
+	}
+}
+
+func TestWeighted_bfsDfs(t *testing.T) {
+	g := NewDirected[int]()
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(2, 4, 1)
+	g.AddEdge(3, 4, 1)
+	g.AddEdge(4, 5, 1)
+	g.AddEdge(5, 1, 1)
+	g.AddEdge(6, 1, 1)
+
+	if got, want := slices.Collect(g.BFS(1)), []int{1, 2, 3, 4, 5}; !slices.Equal(
+		got, want) {
+		t.Errorf("BFS(1)=%v, want %v", got, want)
+	}
+	if got, want := slices.Collect(g.DFS(1)), []int{1, 2, 4, 5, 3}; !slices.Equal(
+		got, want) {
+		t.Errorf("DFS(1)=%v, want %v", got, want)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddEdge("shirt", "tie", 1)
+	g.AddEdge("tie", "jacket", 1)
+	g.AddEdge("pants", "shoes", 1)
+	g.AddEdge("pants", "belt", 1)
+	g.AddEdge("belt", "jacket", 1)
+	g.AddEdge("shirt", "belt", 1)
+	g.AddEdge("socks", "shoes", 1)
+
+	got, ok := g.TopologicalSort()
+	if !ok {
+		t.Fatalf("TopologicalSort() failed")
+	}
+	if len(got) != g.NumVertices() {
+		t.Fatalf("TopologicalSort() len=%v, want %v", len(got), g.NumVertices())
+	}
+	for e := range g.Edges() {
+		if slices.Index(got, e.From) > slices.Index(got, e.To) {
+			t.Fatalf("TopologicalSort()=%v, %v is after %v",
+				got, e.From, e.To)
+		}
+	}
+	if g.HasCycle() {
+		t.Fatalf("HasCycle()=true, want false")
+	}
+
+	g.AddEdge("jacket", "shirt", 1)
+	if _, ok := g.TopologicalSort(); ok {
+		t.Fatalf("TopologicalSort() succeeded, want fail")
+	}
+	if !g.HasCycle() {
+		t.Fatalf("HasCycle()=false, want true")
+	}
+}
+
+func TestHasCycle_undirected(t *testing.T) {
+	g := NewUndirected[int]()
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(4, 3, 1)
+	if g.HasCycle() {
+		t.Fatalf("HasCycle()=true, want false")
+	}
+	g.AddEdge(4, 2, 1)
+	if !g.HasCycle() {
+		t.Fatalf("HasCycle()=false, want true")
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := NewUndirected[string]()
+	g.AddEdge("a", "b", 7)
+	g.AddEdge("a", "c", 9)
+	g.AddEdge("a", "f", 14)
+	g.AddEdge("b", "c", 10)
+	g.AddEdge("b", "d", 15)
+	g.AddEdge("c", "d", 11)
+	g.AddEdge("c", "f", 2)
+	g.AddEdge("d", "e", 6)
+	g.AddEdge("e", "f", 9)
+	g.AddVertices("x")
+
+	path, w, ok := g.ShortestPath("a", "e")
+	if want := []string{"a", "c", "f", "e"}; !ok || w != 20 ||
+		!slices.Equal(path, want) {
+		t.Fatalf("ShortestPath(a,e)=%v,%v,%v, want %v,20,true",
+			path, w, ok, want)
+	}
+	if _, _, ok := g.ShortestPath("a", "x"); ok {
+		t.Fatalf("ShortestPath(a,x) succeeded, want fail")
+	}
+
+	dists := g.ShortestPaths("a")
+	want := map[string]float64{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}
+	if len(dists) != len(want) {
+		t.Fatalf("ShortestPaths(a)=%v, want %v", dists, want)
+	}
+	for k, v := range want {
+		if dists[k] != v {
+			t.Fatalf("ShortestPaths(a)=%v, want %v", dists, want)
+		}
+	}
+}
+
+func TestAStar(t *testing.T) {
+	// A 10x10 grid with a wall.
+	type p struct{ x, y int }
+	g := NewUndirected[p]()
+	for x := range 10 {
+		for y := range 10 {
+			if x == 5 && y < 9 {
+				continue
+			}
+			if x < 9 && !(x == 4 && y < 9) {
+				g.AddEdge(p{x, y}, p{x + 1, y}, 1)
+			}
+			if y < 9 {
+				g.AddEdge(p{x, y}, p{x, y + 1}, 1)
+			}
+		}
+	}
+	h := func(a p) float64 {
+		return math.Abs(float64(a.x-9)) + math.Abs(float64(a.y))
+	}
+	path, w, ok := g.AStar(p{0, 0}, p{9, 0}, h)
+	if !ok || w != 27 {
+		t.Fatalf("AStar(...)=%v,%v, want 27,true", w, ok)
+	}
+	if len(path) != 28 || path[0] != (p{0, 0}) || path[27] != (p{9, 0}) {
+		t.Fatalf("AStar(...)=%v, want path of length 28", path)
+	}
+	if _, w2, _ := g.ShortestPath(p{0, 0}, p{9, 0}); w2 != w {
+		t.Fatalf("ShortestPath(...)=%v, want %v", w2, w)
+	}
+}
@@ -1,28 +1,46 @@
 // Package hashx provides simple hashing functions for various input types.
+//
+// Hash values are calculated with murmur3 by default, or with XXH64 when
+// created with [NewXXH64Seed].
 package hashx
 
 import (
 	"hash"
 	"unsafe"
 
+	"github.com/fluhus/gostuff/gnum"
 	"github.com/spaolacci/murmur3"
 	"golang.org/x/exp/constraints"
 )
 
 // Hashx calculates hash values for various input types.
 type Hashx struct {
-	h   hash.Hash64
-	buf []byte
+	h    hash.Hash64
+	h128 hash128
+	buf  []byte
+}
+
+// A 128-bit hash function.
+type hash128 interface {
+	hash.Hash
+	Sum128() (uint64, uint64)
 }
 
 // NewSeed returns a new Hashx with the given seed.
 func NewSeed(seed uint32) *Hashx {
-	return &Hashx{murmur3.New64WithSeed(seed), make([]byte, 8)}
+	return &Hashx{murmur3.New64WithSeed(seed), murmur3.New128WithSeed(seed),
+		make([]byte, 8)}
 }
 
 // New returns a new Hashx.
 func New() *Hashx {
-	return &Hashx{murmur3.New64(), make([]byte, 8)}
+	return &Hashx{murmur3.New64(), murmur3.New128(), make([]byte, 8)}
+}
+
+// NewXXH64Seed returns a new Hashx that uses XXH64 with the given seed.
+// Its 128-bit hashes are pairs of XXH64 values with different seeds.
+func NewXXH64Seed(seed uint64) *Hashx {
+	return &Hashx{NewXXH64(seed), newXXH64Pair(seed), make([]byte, 8)}
 }
 
 // Bytes returns the hash value of the given byte sequence.
@@ -37,6 +55,18 @@ func (h *Hashx) String(s string) uint64 {
 	return h.Bytes(unsafe.Slice(unsafe.StringData(s), len(s)))
 }
 
+// Bytes128 returns the 128-bit hash value of the given byte sequence.
+func (h *Hashx) Bytes128(b []byte) (uint64, uint64) {
+	h.h128.Reset()
+	h.h128.Write(b)
+	return h.h128.Sum128()
+}
+
+// String128 returns the 128-bit hash value of the given string.
+func (h *Hashx) String128(s string) (uint64, uint64) {
+	return h.Bytes128(unsafe.Slice(unsafe.StringData(s), len(s)))
+}
+
 // IntHashx returns the hash value of the given integer,
 // using the given Hashx instance.
 func IntHashx[I constraints.Integer](h *Hashx, i I) uint64 {
@@ -49,6 +79,16 @@ func IntHashx[I constraints.Integer](h *Hashx, i I) uint64 {
 	return h.h.Sum64()
 }
 
+// SliceHashx returns the hash value of the given numbers,
+// using the given Hashx instance.
+// The hash is calculated over the numbers' in-memory representation,
+// so it depends on the platform's byte order.
+func SliceHashx[N gnum.Number](h *Hashx, s []N) uint64 {
+	var zero N
+	n := len(s) * int(unsafe.Sizeof(zero))
+	return h.Bytes(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), n))
+}
+
 // The default hashx.
 var dflt = New()
 
@@ -60,3 +100,17 @@ func String(s string) uint64 { return dflt.String(s) }
 
 // Int returns the hash value of the given integer.
 func Int[I constraints.Integer](i I) uint64 { return IntHashx(dflt, i) }
+
+// Bytes128 returns the 128-bit hash value of the given byte sequence.
+func Bytes128(b []byte) (uint64, uint64) { return dflt.Bytes128(b) }
+
+// String128 returns the 128-bit hash value of the given string.
+func String128(s string) (uint64, uint64) { return dflt.String128(s) }
+
+// Slice returns the hash value of the given numbers.
+// The hash is calculated over the numbers' in-memory representation,
+// so it depends on the platform's byte order.
+func Slice[N gnum.Number](s []N) uint64 { return SliceHashx(dflt, s) }
+
+// Value returns the hash value of v. See [Hashx.Value].
+func Value(v any) uint64 { return dflt.Value(v) }
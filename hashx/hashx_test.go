@@ -1,6 +1,7 @@
 package hashx
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/fluhus/gostuff/sets"
@@ -52,3 +53,99 @@ func TestBytesString(t *testing.T) {
 		t.Errorf("len(hashes)=%v, want %v", len(hashes), n)
 	}
 }
+
+func TestBytes128(t *testing.T) {
+	hx := New()
+	a1, a2 := hx.Bytes128([]byte("hello"))
+	b1, b2 := String128("hello")
+	if a1 != b1 || a2 != b2 {
+		t.Fatalf("Bytes128(hello)=%x,%x, String128(hello)=%x,%x, want equal",
+			a1, a2, b1, b2)
+	}
+	c1, c2 := String128("hellO")
+	if a1 == c1 || a2 == c2 {
+		t.Fatalf("String128(hello)=%x,%x, String128(hellO)=%x,%x, want different",
+			a1, a2, c1, c2)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	hashes := sets.Set[uint64]{}
+	inputs := [][]float64{nil, {0}, {1}, {0, 0}, {1, 0}, {0, 1}, {1, 2, 3}}
+	for _, input := range inputs {
+		hashes.Add(Slice(input))
+	}
+	if len(hashes) != len(inputs) {
+		t.Errorf("len(hashes)=%v, want %v", len(hashes), len(inputs))
+	}
+	if Slice([]int32{1, 2}) != Slice([]int32{1, 2}) {
+		t.Errorf("Slice({1,2}) returned different values")
+	}
+}
+
+func TestValue(t *testing.T) {
+	type inner struct {
+		A string
+		b []int
+	}
+	type outer struct {
+		I *inner
+		F float32
+		U uint8
+	}
+	x := outer{&inner{"a", []int{1, 2}}, 1.5, 3}
+	y := outer{&inner{"a", []int{1, 2}}, 1.5, 3}
+	if Value(x) != Value(y) {
+		t.Fatalf("Value(%v) != Value(%v)", x, y)
+	}
+
+	hashes := sets.Set[uint64]{}
+	inputs := []any{
+		x,
+		outer{&inner{"a", []int{1, 3}}, 1.5, 3},
+		outer{&inner{"", []int{1, 2}}, 1.5, 3},
+		outer{nil, 1.5, 3},
+		outer{&inner{"a", []int{1, 2}}, 1.5, 4},
+		[]string{"ab", "c"},
+		[]string{"a", "bc"},
+		int(1),
+		uint(1),
+		float64(1),
+		map[string]int{"a": 1},
+		map[string]int{"a": 2},
+		map[string]int{"a": 1, "b": 2},
+	}
+	for _, input := range inputs {
+		hashes.Add(Value(input))
+	}
+	if len(hashes) != len(inputs) {
+		t.Errorf("len(hashes)=%v, want %v", len(hashes), len(inputs))
+	}
+}
+
+func TestValue_map(t *testing.T) {
+	a := map[int]string{}
+	b := map[int]string{}
+	for i := range 100 {
+		a[i] = fmt.Sprint(i)
+		b[99-i] = fmt.Sprint(99 - i)
+	}
+	if Value(a) != Value(b) {
+		t.Fatalf("Value(%v) != Value(%v)", a, b)
+	}
+}
+
+func TestNewXXH64Seed(t *testing.T) {
+	hx := NewXXH64Seed(5)
+	x := NewXXH64(5)
+	x.Write([]byte("hello"))
+	if got, want := hx.String("hello"), x.Sum64(); got != want {
+		t.Fatalf("String(hello)=%x, want %x", got, want)
+	}
+	a1, a2 := hx.String128("hello")
+	b1, b2 := hx.String128("hellO")
+	if a1 == b1 || a2 == b2 || a1 == a2 {
+		t.Fatalf("String128(hello)=%x,%x, String128(hellO)=%x,%x, "+
+			"want all different", a1, a2, b1, b2)
+	}
+}
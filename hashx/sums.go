@@ -0,0 +1,48 @@
+package hashx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"os"
+)
+
+// Sums holds several checksums of the same data.
+type Sums struct {
+	Size   int64    // Number of bytes
+	CRC64  uint64   // CRC-64 with the ECMA polynomial
+	SHA256 [32]byte // SHA-256
+	XXH64  uint64   // XXH64 with seed 0
+}
+
+// String returns a readable representation of the sums.
+func (s Sums) String() string {
+	return fmt.Sprintf("size=%d crc64=%016x sha256=%s xxh64=%016x",
+		s.Size, s.CRC64, hex.EncodeToString(s.SHA256[:]), s.XXH64)
+}
+
+// SumReader computes the checksums of r's data in a single pass.
+func SumReader(r io.Reader) (Sums, error) {
+	crc := crc64.New(crc64.MakeTable(crc64.ECMA))
+	sha := sha256.New()
+	xx := NewXXH64(0)
+	n, err := io.Copy(io.MultiWriter(crc, sha, xx), r)
+	if err != nil {
+		return Sums{}, err
+	}
+	s := Sums{Size: n, CRC64: crc.Sum64(), XXH64: xx.Sum64()}
+	sha.Sum(s.SHA256[:0])
+	return s, nil
+}
+
+// SumFile computes the checksums of a file's raw bytes in a single pass.
+func SumFile(file string) (Sums, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Sums{}, err
+	}
+	defer f.Close()
+	return SumReader(f)
+}
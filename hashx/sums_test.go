@@ -0,0 +1,63 @@
+package hashx
+
+import (
+	"crypto/sha256"
+	"hash/crc64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestXXH64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"hello world", 0x45ab6734b21e6968},
+		{strings.Repeat("abcdefgh", 10), 0xc8d02d35720862d5},
+	}
+	for _, test := range tests {
+		h := NewXXH64(0)
+		h.Write([]byte(test.input))
+		if got := h.Sum64(); got != test.want {
+			t.Errorf("XXH64(%q)=%x, want %x", test.input, got, test.want)
+		}
+
+		// Byte-by-byte writes.
+		h.Reset()
+		for i := range len(test.input) {
+			h.Write([]byte(test.input[i : i+1]))
+		}
+		if got := h.Sum64(); got != test.want {
+			t.Errorf("XXH64(%q) by bytes=%x, want %x",
+				test.input, got, test.want)
+		}
+	}
+}
+
+func TestSumFile(t *testing.T) {
+	data := []byte(strings.Repeat("hello world ", 1000))
+	file := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := SumFile(file)
+	if err != nil {
+		t.Fatalf("SumFile(%q) failed: %v", file, err)
+	}
+	xx := NewXXH64(0)
+	xx.Write(data)
+	want := Sums{
+		Size:   int64(len(data)),
+		CRC64:  crc64.Checksum(data, crc64.MakeTable(crc64.ECMA)),
+		SHA256: sha256.Sum256(data),
+		XXH64:  xx.Sum64(),
+	}
+	if got != want {
+		t.Errorf("SumFile(%q)=%v, want %v", file, got, want)
+	}
+}
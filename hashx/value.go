@@ -0,0 +1,94 @@
+package hashx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Value returns the hash value of v, calculated over its contents using
+// reflection.
+//
+// Supported types are bool, int*, uint*, float*, complex*, string,
+// and arrays, slices, maps, pointers and structs of these types.
+// All struct fields are hashed, including unexported ones.
+// Pointers are followed, so equal values behind different pointers have
+// equal hashes. Maps are hashed regardless of iteration order.
+// Values of different kinds have different hashes, so int(1) and uint(1)
+// differ.
+// Panics if v contains an unsupported type, such as a function or a
+// channel, or a pointer cycle.
+func (h *Hashx) Value(v any) uint64 {
+	buf := appendValue(nil, reflect.ValueOf(v), 0)
+	return h.Bytes(buf)
+}
+
+// Maximal pointer depth, for catching pointer cycles.
+const maxValueDepth = 1000
+
+// Appends an unambiguous binary encoding of v to buf.
+func appendValue(buf []byte, v reflect.Value, depth int) []byte {
+	if depth > maxValueDepth {
+		panic("maximal depth exceeded, value may contain a pointer cycle")
+	}
+	buf = append(buf, byte(v.Kind()))
+	switch v.Kind() {
+	case reflect.Invalid:
+		return buf
+	case reflect.Bool:
+		if v.Bool() {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return binary.LittleEndian.AppendUint64(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(buf,
+			math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(real(c)))
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(imag(c)))
+	case reflect.String:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		return append(buf, v.String()...)
+	case reflect.Slice, reflect.Array:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		for i := range v.Len() {
+			buf = appendValue(buf, v.Index(i), depth)
+		}
+		return buf
+	case reflect.Map:
+		// Sort the encoded entries, to be independent of iteration order.
+		var entries [][]byte
+		for it := v.MapRange(); it.Next(); {
+			e := appendValue(nil, it.Key(), depth)
+			entries = append(entries, appendValue(e, it.Value(), depth))
+		}
+		slices.SortFunc(entries, bytes.Compare)
+		buf = binary.AppendUvarint(buf, uint64(len(entries)))
+		for _, e := range entries {
+			buf = append(buf, e...)
+		}
+		return buf
+	case reflect.Struct:
+		for i := range v.NumField() {
+			buf = appendValue(buf, v.Field(i), depth)
+		}
+		return buf
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return append(buf, 0)
+		}
+		return appendValue(append(buf, 1), v.Elem(), depth+1)
+	default:
+		panic(fmt.Sprintf("unsupported type: %v", v.Type()))
+	}
+}
@@ -0,0 +1,169 @@
+package hashx
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// XXH64 primes.
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// An XXH64 streaming digest.
+type xxh64 struct {
+	seed  uint64
+	v     [4]uint64
+	total uint64
+	mem   [32]byte
+	n     int // Number of buffered bytes in mem.
+}
+
+// NewXXH64 returns a new XXH64 hash with the given seed.
+func NewXXH64(seed uint64) hash.Hash64 {
+	h := &xxh64{seed: seed}
+	h.Reset()
+	return h
+}
+
+func (h *xxh64) Reset() {
+	h.v = [4]uint64{h.seed + xxPrime1 + xxPrime2, h.seed + xxPrime2, h.seed,
+		h.seed - xxPrime1}
+	h.total = 0
+	h.n = 0
+}
+
+func (h *xxh64) Size() int {
+	return 8
+}
+
+func (h *xxh64) BlockSize() int {
+	return 32
+}
+
+func (h *xxh64) Write(b []byte) (int, error) {
+	n := len(b)
+	h.total += uint64(n)
+	if h.n+len(b) < 32 {
+		h.n += copy(h.mem[h.n:], b)
+		return n, nil
+	}
+	if h.n > 0 {
+		c := copy(h.mem[h.n:], b)
+		h.blocks(h.mem[:])
+		b = b[c:]
+		h.n = 0
+	}
+	b = h.blocks(b)
+	h.n = copy(h.mem[:], b)
+	return n, nil
+}
+
+// Consumes whole 32-byte blocks of b and returns the remainder.
+func (h *xxh64) blocks(b []byte) []byte {
+	v := &h.v
+	for ; len(b) >= 32; b = b[32:] {
+		v[0] = xxRound(v[0], binary.LittleEndian.Uint64(b))
+		v[1] = xxRound(v[1], binary.LittleEndian.Uint64(b[8:]))
+		v[2] = xxRound(v[2], binary.LittleEndian.Uint64(b[16:]))
+		v[3] = xxRound(v[3], binary.LittleEndian.Uint64(b[24:]))
+	}
+	return b
+}
+
+func (h *xxh64) Sum64() uint64 {
+	var x uint64
+	if h.total >= 32 {
+		v := h.v
+		x = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) +
+			bits.RotateLeft64(v[2], 12) + bits.RotateLeft64(v[3], 18)
+		for _, vi := range v {
+			x = xxMerge(x, vi)
+		}
+	} else {
+		x = h.seed + xxPrime5
+	}
+	x += h.total
+
+	b := h.mem[:h.n]
+	for ; len(b) >= 8; b = b[8:] {
+		x ^= xxRound(0, binary.LittleEndian.Uint64(b))
+		x = bits.RotateLeft64(x, 27)*xxPrime1 + xxPrime4
+	}
+	if len(b) >= 4 {
+		x ^= uint64(binary.LittleEndian.Uint32(b)) * xxPrime1
+		x = bits.RotateLeft64(x, 23)*xxPrime2 + xxPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		x ^= uint64(c) * xxPrime5
+		x = bits.RotateLeft64(x, 11) * xxPrime1
+	}
+
+	x ^= x >> 33
+	x *= xxPrime2
+	x ^= x >> 29
+	x *= xxPrime3
+	x ^= x >> 32
+	return x
+}
+
+func (h *xxh64) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint64(b, h.Sum64())
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMerge(acc, v uint64) uint64 {
+	acc ^= xxRound(0, v)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// Seed offset of the second half of a 128-bit XXH64 pair.
+const xxPairSeed = xxPrime3
+
+// Two XXH64 hashes with different seeds, forming a 128-bit hash.
+type xxh64Pair struct {
+	a, b hash.Hash64
+}
+
+func newXXH64Pair(seed uint64) *xxh64Pair {
+	return &xxh64Pair{NewXXH64(seed), NewXXH64(seed + xxPairSeed)}
+}
+
+func (h *xxh64Pair) Write(b []byte) (int, error) {
+	h.a.Write(b)
+	return h.b.Write(b)
+}
+
+func (h *xxh64Pair) Reset() {
+	h.a.Reset()
+	h.b.Reset()
+}
+
+func (h *xxh64Pair) Size() int {
+	return 16
+}
+
+func (h *xxh64Pair) BlockSize() int {
+	return 32
+}
+
+func (h *xxh64Pair) Sum128() (uint64, uint64) {
+	return h.a.Sum64(), h.b.Sum64()
+}
+
+func (h *xxh64Pair) Sum(b []byte) []byte {
+	x, y := h.Sum128()
+	b = binary.BigEndian.AppendUint64(b, x)
+	return binary.BigEndian.AppendUint64(b, y)
+}
@@ -5,8 +5,6 @@ import (
 	"math/rand/v2"
 	"slices"
 	"testing"
-
-	"github.com/fluhus/gostuff/snm"
 )
 
 func TestHeap(t *testing.T) {
@@ -78,9 +76,10 @@ func TestHeap_pushSlice(t *testing.T) {
 
 func Benchmark(b *testing.B) {
 	for _, n := range []int{1000, 10000, 100000, 1000000} {
-		nums := snm.Slice(n, func(i int) int {
-			return rand.Int()
-		})
+		nums := make([]int, n)
+		for i := range nums {
+			nums[i] = rand.Int()
+		}
 		b.Run(fmt.Sprint("Heap.Push.", n), func(b *testing.B) {
 			for range b.N {
 				h := Min[int]()
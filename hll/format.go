@@ -0,0 +1,109 @@
+package hll
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fluhus/gostuff/internal/mmap"
+	"github.com/fluhus/gostuff/internal/sketchfmt"
+)
+
+// WriteTo writes the counter to w as a fixed-size header followed by the
+// raw registers. The hash function is not written.
+// The result can be read with ReadFrom or memory-mapped with [Mmap].
+// Implements [io.WriterTo].
+func (h *HLL[T]) WriteTo(w io.Writer) (int64, error) {
+	hdr := sketchfmt.Header{
+		Kind: sketchfmt.KindHLL,
+		P1:   uint32(h.nbits),
+		N:    uint64(len(h.counters)),
+	}
+	return sketchfmt.Write(w, hdr, h.counters)
+}
+
+// ReadFrom reads a counter written by WriteTo and sets this counter's state
+// to match it. The counter keeps its hash function, which should be the
+// one used by the written counter.
+// Implements [io.ReaderFrom].
+func (h *HLL[T]) ReadFrom(r io.Reader) (int64, error) {
+	hdr, data, n, err := sketchfmt.ReadData(r, sketchfmt.KindHLL, checkHeader)
+	if err != nil {
+		return n, err
+	}
+	if err := h.setState(hdr, data); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// A Mapped is a read-only counter backed by a memory-mapped file.
+// Processes that map the same file share its memory.
+//
+// Calling Add or AddHLL on a mapped counter panics.
+type Mapped[T any] struct {
+	*HLL[T]
+	data []byte
+}
+
+// Mmap maps a counter file written by WriteTo to memory.
+// h should be the hash function used by the written counter.
+// The counter must be released with Close.
+func Mmap[T any](file string, h func(T) uint64) (*Mapped[T], error) {
+	data, err := mmap.Map(file)
+	if err != nil {
+		return nil, err
+	}
+	hdr, b, err := sketchfmt.Map(data, sketchfmt.KindHLL)
+	if err != nil {
+		mmap.Unmap(data)
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	hll := &HLL[T]{h: h}
+	if err := hll.setState(hdr, b); err != nil {
+		mmap.Unmap(data)
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	hll.ro = true
+	return &Mapped[T]{hll, data}, nil
+}
+
+// Close unmaps the counter's file. The counter must not be used after
+// calling Close.
+func (m *Mapped[T]) Close() error {
+	return mmap.Unmap(m.data)
+}
+
+// Maximal logSize accepted when decoding a counter.
+const maxLogSize = 30
+
+// Checks that a header describes a valid counter.
+func checkHeader(hdr sketchfmt.Header) error {
+	if hdr.P1 < 4 || hdr.P1 > maxLogSize {
+		return fmt.Errorf("bad logSize: %d", hdr.P1)
+	}
+	if m := uint64(1) << hdr.P1; hdr.N != m {
+		return fmt.Errorf("bad number of registers: %d, want %d", hdr.N, m)
+	}
+	return nil
+}
+
+// Sets the counter's parameters and registers according to a decoded header.
+func (h *HLL[T]) setState(hdr sketchfmt.Header, data []byte) error {
+	if err := checkHeader(hdr); err != nil {
+		return err
+	}
+	m := 1 << hdr.P1
+	h.counters = data
+	h.nbits = int(hdr.P1)
+	h.m = m
+	h.mask = uint64(m - 1)
+	h.ro = false
+	return nil
+}
+
+// Panics if the counter is read-only.
+func (h *HLL[T]) checkWritable() {
+	if h.ro {
+		panic("counter is read-only")
+	}
+}
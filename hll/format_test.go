@@ -0,0 +1,80 @@
+package hll
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluhus/gostuff/internal/sketchfmt"
+)
+
+func TestWriteToReadFrom(t *testing.T) {
+	h1 := newIntHLL()
+	for i := range 10000 {
+		h1.Add(i)
+	}
+	buf := bytes.NewBuffer(nil)
+	n, err := h1.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("WriteTo(...) failed: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("WriteTo(...)=%v, want %v", n, buf.Len())
+	}
+	h2 := newIntHLL()
+	if _, err := h2.ReadFrom(buf); err != nil {
+		t.Fatalf("ReadFrom(...) failed: %v", err)
+	}
+	if h1.ApproxCount() != h2.ApproxCount() {
+		t.Fatalf("ApproxCount()=%v, want %v",
+			h2.ApproxCount(), h1.ApproxCount())
+	}
+	if h2.LogSize() != h1.LogSize() {
+		t.Fatalf("LogSize()=%v, want %v", h2.LogSize(), h1.LogSize())
+	}
+}
+
+func TestMmap(t *testing.T) {
+	h1 := newIntHLL()
+	for i := range 10000 {
+		h1.Add(i)
+	}
+	file := filepath.Join(t.TempDir(), "hll")
+	buf := bytes.NewBuffer(nil)
+	h1.WriteTo(buf)
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h2, err := Mmap(file, newIntHLL().h)
+	if err != nil {
+		t.Fatalf("Mmap(...) failed: %v", err)
+	}
+	defer h2.Close()
+	if h1.ApproxCount() != h2.ApproxCount() {
+		t.Fatalf("ApproxCount()=%v, want %v",
+			h2.ApproxCount(), h1.ApproxCount())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Add on mapped counter did not panic")
+		}
+	}()
+	h2.Add(1)
+}
+
+func TestReadFrom_badHeader(t *testing.T) {
+	hdrs := []sketchfmt.Header{
+		{Kind: sketchfmt.KindHLL, P1: 32, N: 1 << 32},
+		{Kind: sketchfmt.KindHLL, P1: 8, N: 1 << 40},
+		{Kind: sketchfmt.KindHLL, P1: 8, N: 100},
+	}
+	for _, hdr := range hdrs {
+		h := newIntHLL()
+		if _, err := h.ReadFrom(bytes.NewReader(hdr.Bytes())); err == nil {
+			t.Errorf("ReadFrom(%+v) succeeded, want error", hdr)
+		}
+	}
+}
@@ -42,6 +42,7 @@ type HLL[T any] struct {
 	nbits    int
 	m        int
 	mask     uint64
+	ro       bool // Read-only, for memory-mapped counters.
 }
 
 // New creates a new HyperLogLog counter.
@@ -63,6 +64,7 @@ func New[T any](logSize int, h func(T) uint64) *HLL[T] {
 
 // Add adds v to the counter. Calls hash once.
 func (h *HLL[T]) Add(t T) {
+	h.checkWritable()
 	hash := h.h(t)
 	idx := hash & h.mask
 	fp := hash >> h.nbits
@@ -129,6 +131,7 @@ func (h *HLL[T]) nzeros(a uint64) int {
 // assuming they use the same hash function.
 // The result is equivalent to adding all the values of other to h.
 func (h *HLL[T]) AddHLL(other *HLL[T]) {
+	h.checkWritable()
 	if len(h.counters) != len(other.counters) {
 		panic("merging HLLs with different sizes")
 	}
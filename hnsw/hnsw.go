@@ -0,0 +1,410 @@
+// Package hnsw provides an approximate nearest-neighbor index over float32
+// vectors, using hierarchical navigable small world (HNSW) graphs.
+//
+// # Parameters
+//
+// M is the number of neighbors each vector links to in each layer
+// (twice that in the bottom layer). Larger values improve recall at the
+// cost of memory and insertion time. Typical values are 8-48.
+//
+// EfConstruction is the size of the candidate list during insertion.
+// Larger values improve the index quality at the cost of insertion time.
+// Typical values are 100-500.
+//
+// EfSearch is the size of the candidate list during search, and can be
+// changed at any time. Larger values improve recall at the cost of search
+// time. It is always at least the number of requested neighbors.
+//
+// # Citation
+//
+// Malkov, Yu A., and Dmitry A. Yashunin. "Efficient and robust approximate
+// nearest neighbor search using hierarchical navigable small world graphs."
+// IEEE transactions on pattern analysis and machine intelligence 42.4 (2018):
+// 824-836.
+package hnsw
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+
+	"github.com/fluhus/gostuff/bnry"
+	"github.com/fluhus/gostuff/heaps"
+)
+
+// A Metric determines how distances between vectors are calculated.
+type Metric int
+
+const (
+	// Euclidean distance.
+	Euclidean Metric = iota
+	// Cosine distance, which is 1 minus the cosine similarity.
+	// Vectors are normalized upon insertion.
+	Cosine
+)
+
+// An Index is an HNSW approximate nearest-neighbor index.
+// An index is not safe for concurrent use.
+type Index struct {
+	EfSearch int // Candidate list size for searching.
+
+	dim    int
+	metric Metric
+	m      int
+	efc    int
+	ml     float64     // Level generation factor.
+	vecs   [][]float32 // Vector of each node.
+	links  [][][]int32 // Neighbors of each node in each of its layers.
+	entry  int         // Entry point, -1 for an empty index.
+	rng    *rand.Rand
+}
+
+// A Result is a single search result.
+type Result struct {
+	ID   int     // ID of the found vector, as returned by Add.
+	Dist float32 // Distance from the query.
+}
+
+// New returns an empty index over vectors of dimension dim.
+func New(dim int, metric Metric, m, efConstruction int) *Index {
+	if dim < 1 {
+		panic(fmt.Sprintf("bad dim: %d", dim))
+	}
+	if m < 2 {
+		panic(fmt.Sprintf("bad M: %d, should be at least 2", m))
+	}
+	if efConstruction < 1 {
+		panic(fmt.Sprintf("bad efConstruction: %d", efConstruction))
+	}
+	if metric != Euclidean && metric != Cosine {
+		panic(fmt.Sprintf("bad metric: %d", metric))
+	}
+	return &Index{
+		EfSearch: 50,
+		dim:      dim,
+		metric:   metric,
+		m:        m,
+		efc:      efConstruction,
+		ml:       1 / math.Log(float64(m)),
+		entry:    -1,
+		rng:      rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// Len returns the number of vectors in the index.
+func (x *Index) Len() int {
+	return len(x.vecs)
+}
+
+// Vector returns the vector with the given ID.
+// For the cosine metric, the vector is normalized.
+// The returned slice should not be modified.
+func (x *Index) Vector(id int) []float32 {
+	return x.vecs[id]
+}
+
+// Add inserts a copy of v to the index and returns its ID.
+// IDs are sequential, starting at 0.
+func (x *Index) Add(v []float32) int {
+	v = x.prepare(v)
+	id := len(x.vecs)
+	level := int(-math.Log(1-x.rng.Float64()) * x.ml)
+	x.vecs = append(x.vecs, v)
+	x.links = append(x.links, make([][]int32, level+1))
+
+	if x.entry == -1 {
+		x.entry = id
+		return id
+	}
+
+	ep := x.entry
+	top := len(x.links[ep]) - 1
+	for l := top; l > level; l-- {
+		ep = x.greedy(v, ep, l)
+	}
+	eps := []int{ep}
+	for l := min(level, top); l >= 0; l-- {
+		found := x.searchLayer(v, eps, x.efc, l)
+		neighbors := found
+		if len(neighbors) > x.m {
+			neighbors = neighbors[:x.m]
+		}
+		for _, nb := range neighbors {
+			x.links[id][l] = append(x.links[id][l], int32(nb.ID))
+			x.link(nb.ID, id, l)
+		}
+		eps = eps[:0]
+		for _, f := range found {
+			eps = append(eps, f.ID)
+		}
+	}
+	if level > top {
+		x.entry = id
+	}
+	return id
+}
+
+// SearchKNN returns the approximate k nearest neighbors of q,
+// ordered from closest to farthest.
+func (x *Index) SearchKNN(q []float32, k int) []Result {
+	if x.entry == -1 || k < 1 {
+		return nil
+	}
+	q = x.prepare(q)
+	ep := x.entry
+	for l := len(x.links[ep]) - 1; l > 0; l-- {
+		ep = x.greedy(q, ep, l)
+	}
+	found := x.searchLayer(q, []int{ep}, max(x.EfSearch, k), 0)
+	if len(found) > k {
+		found = found[:k]
+	}
+	return found
+}
+
+// Adds a link from a to b in layer l, pruning a's links if needed.
+func (x *Index) link(a, b, l int) {
+	links := append(x.links[a][l], int32(b))
+	maxLinks := x.m
+	if l == 0 {
+		maxLinks *= 2
+	}
+	if len(links) > maxLinks {
+		// Drop the farthest neighbor.
+		va := x.vecs[a]
+		worst, worstDist := 0, float32(-1)
+		for i, nb := range links {
+			if d := x.dist(va, x.vecs[nb]); d > worstDist {
+				worst, worstDist = i, d
+			}
+		}
+		links[worst] = links[len(links)-1]
+		links = links[:len(links)-1]
+	}
+	x.links[a][l] = links
+}
+
+// Returns the node closest to q in layer l, walking greedily from ep.
+func (x *Index) greedy(q []float32, ep, l int) int {
+	best := x.dist(q, x.vecs[ep])
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range x.links[ep][l] {
+			if d := x.dist(q, x.vecs[nb]); d < best {
+				ep, best, changed = int(nb), d, true
+			}
+		}
+	}
+	return ep
+}
+
+// Returns up to ef nodes closest to q in layer l, starting from eps,
+// ordered from closest to farthest.
+func (x *Index) searchLayer(q []float32, eps []int, ef, l int) []Result {
+	closer := func(a, b Result) bool { return a.Dist < b.Dist }
+	farther := func(a, b Result) bool { return a.Dist > b.Dist }
+	cand := heaps.New(closer)
+	best := heaps.New(farther)
+	visited := map[int]struct{}{}
+	for _, ep := range eps {
+		visited[ep] = struct{}{}
+		r := Result{ep, x.dist(q, x.vecs[ep])}
+		cand.Push(r)
+		best.Push(r)
+	}
+	for best.Len() > ef {
+		best.Pop()
+	}
+	for cand.Len() > 0 {
+		c := cand.Pop()
+		if c.Dist > best.Head().Dist {
+			break
+		}
+		for _, nb := range x.links[c.ID][l] {
+			if _, ok := visited[int(nb)]; ok {
+				continue
+			}
+			visited[int(nb)] = struct{}{}
+			d := x.dist(q, x.vecs[nb])
+			if best.Len() < ef || d < best.Head().Dist {
+				r := Result{int(nb), d}
+				cand.Push(r)
+				best.Push(r)
+				if best.Len() > ef {
+					best.Pop()
+				}
+			}
+		}
+	}
+	result := make([]Result, best.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = best.Pop()
+	}
+	return result
+}
+
+// Returns the distance between a and b according to the index's metric.
+func (x *Index) dist(a, b []float32) float32 {
+	if x.metric == Cosine {
+		var dot float32
+		for i := range a {
+			dot += a[i] * b[i]
+		}
+		return 1 - dot
+	}
+	var sum float32
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return float32(math.Sqrt(float64(sum)))
+}
+
+// Returns a copy of v, normalized if needed.
+func (x *Index) prepare(v []float32) []float32 {
+	if len(v) != x.dim {
+		panic(fmt.Sprintf("vector has dimension %d, want %d", len(v), x.dim))
+	}
+	v = append([]float32(nil), v...)
+	if x.metric == Cosine {
+		var sum float64
+		for _, f := range v {
+			sum += float64(f) * float64(f)
+		}
+		if sum > 0 {
+			norm := float32(math.Sqrt(sum))
+			for i := range v {
+				v[i] /= norm
+			}
+		}
+	}
+	return v
+}
+
+// Encode writes this index to the stream. Can be reproduced later with
+// Decode.
+func (x *Index) Encode(w io.Writer) error {
+	bw := bnry.NewWriter(w)
+	if err := bw.Write(x.dim, int(x.metric), x.m, x.efc, x.EfSearch,
+		x.entry, len(x.vecs)); err != nil {
+		return err
+	}
+	for i, v := range x.vecs {
+		if err := bw.Write(v, len(x.links[i])); err != nil {
+			return err
+		}
+		for _, l := range x.links[i] {
+			if err := bw.Write(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Decode reads an encoded index from the stream and sets this index's state
+// to match it. Destroys the previously existing state of this index.
+func (x *Index) Decode(r io.ByteReader) error {
+	var dim, metric, m, efc, efs, entry, n int
+	if err := bnry.Read(r, &dim, &metric, &m, &efc, &efs, &entry,
+		&n); err != nil {
+		return err
+	}
+	if dim < 1 || m < 2 || efc < 1 || n < 0 || entry < -1 || entry >= n ||
+		(entry == -1) != (n == 0) ||
+		(metric != int(Euclidean) && metric != int(Cosine)) {
+		return fmt.Errorf("bad index parameters")
+	}
+	nu := New(dim, Metric(metric), m, efc)
+	nu.EfSearch = efs
+	nu.entry = entry
+	// Slices grow as data is read, and slice lengths are checked before
+	// allocating, so corrupt lengths do not cause large allocations.
+	for i := range n {
+		vec, err := readSlice[float32](r, dim, dim)
+		if err != nil {
+			return fmt.Errorf("vector #%d: %w", i, err)
+		}
+		var nlevels int
+		if err := bnry.Read(r, &nlevels); err != nil {
+			return fmt.Errorf("vector #%d: %w", i, notEOF(err))
+		}
+		if nlevels < 1 || nlevels > maxLevels {
+			return fmt.Errorf("vector #%d: bad number of levels: %d",
+				i, nlevels)
+		}
+		var links [][]int32
+		for range nlevels {
+			ll, err := readSlice[int32](r, 0, 2*m)
+			if err != nil {
+				return fmt.Errorf("vector #%d: %w", i, err)
+			}
+			links = append(links, ll)
+		}
+		nu.vecs = append(nu.vecs, vec)
+		nu.links = append(nu.links, links)
+	}
+	if err := nu.checkLinks(); err != nil {
+		return err
+	}
+	*x = *nu
+	return nil
+}
+
+// Maximal number of levels of a decoded node. Generated levels are far
+// below it.
+const maxLevels = 64
+
+// Checks that links point to existing nodes and levels, and that the entry
+// point is on the top level.
+func (x *Index) checkLinks() error {
+	if x.entry == -1 {
+		return nil
+	}
+	top := len(x.links[x.entry])
+	for i, links := range x.links {
+		if len(links) > top {
+			return fmt.Errorf("vector #%d: level %d above entry point level %d",
+				i, len(links)-1, top-1)
+		}
+		for l, ll := range links {
+			for _, nb := range ll {
+				if nb < 0 || int(nb) >= len(x.links) ||
+					len(x.links[nb]) <= l {
+					return fmt.Errorf("vector #%d: bad link %d at level %d",
+						i, nb, l)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Reads a slice encoded by bnry, one element at a time.
+// Returns an error if its length is not in [minLen,maxLen].
+func readSlice[T int32 | float32](r io.ByteReader, minLen, maxLen int,
+) ([]T, error) {
+	var n uint64
+	if err := bnry.Read(r, &n); err != nil {
+		return nil, notEOF(err)
+	}
+	if n < uint64(minLen) || n > uint64(maxLen) {
+		return nil, fmt.Errorf("bad slice length: %d", n)
+	}
+	s := make([]T, n)
+	for i := range s {
+		if err := bnry.Read(r, &s[i]); err != nil {
+			return nil, notEOF(err)
+		}
+	}
+	return s, nil
+}
+
+// Converts EOF to ErrUnexpectedEOF.
+func notEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package hnsw
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/fluhus/gostuff/bnry"
+)
+
+func TestSearchKNN(t *testing.T) {
+	for _, metric := range []Metric{Euclidean, Cosine} {
+		const n, dim, k = 2000, 16, 10
+		x := New(dim, metric, 16, 200)
+		vecs := randomVectors(n, dim)
+		for i, v := range vecs {
+			if id := x.Add(v); id != i {
+				t.Fatalf("Add(...)=%v, want %v", id, i)
+			}
+		}
+		if x.Len() != n {
+			t.Fatalf("Len()=%v, want %v", x.Len(), n)
+		}
+
+		hits := 0
+		queries := randomVectors(100, dim)
+		for _, q := range queries {
+			want := bruteForce(x, q, k)
+			got := x.SearchKNN(q, k)
+			if len(got) != k {
+				t.Fatalf("SearchKNN(...) len=%v, want %v", len(got), k)
+			}
+			if !slices.IsSortedFunc(got, func(a, b Result) int {
+				return cmp.Compare(a.Dist, b.Dist)
+			}) {
+				t.Fatalf("SearchKNN(...)=%v, want sorted", got)
+			}
+			for _, r := range got {
+				if slices.Contains(want, r.ID) {
+					hits++
+				}
+			}
+		}
+		recall := float64(hits) / float64(len(queries)*k)
+		if recall < 0.9 {
+			t.Errorf("metric %v: recall=%v, want >=0.9", metric, recall)
+		}
+	}
+}
+
+func TestSearchKNN_exact(t *testing.T) {
+	x := New(2, Euclidean, 4, 10)
+	x.Add([]float32{0, 0})
+	x.Add([]float32{1, 0})
+	x.Add([]float32{5, 5})
+	got := x.SearchKNN([]float32{0.9, 0.1}, 2)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 0 {
+		t.Fatalf("SearchKNN(...)=%v, want IDs 1,0", got)
+	}
+	if got := New(2, Cosine, 4, 10).SearchKNN([]float32{1, 1}, 3); got != nil {
+		t.Fatalf("SearchKNN on empty index=%v, want nil", got)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	x := New(8, Cosine, 8, 50)
+	for _, v := range randomVectors(300, 8) {
+		x.Add(v)
+	}
+	buf := &bytes.Buffer{}
+	if err := x.Encode(buf); err != nil {
+		t.Fatalf("Encode(...) failed: %v", err)
+	}
+	y := &Index{}
+	if err := y.Decode(bufio.NewReader(buf)); err != nil {
+		t.Fatalf("Decode(...) failed: %v", err)
+	}
+	for _, q := range randomVectors(20, 8) {
+		a, b := x.SearchKNN(q, 5), y.SearchKNN(q, 5)
+		if !slices.Equal(a, b) {
+			t.Fatalf("SearchKNN after Decode=%v, want %v", b, a)
+		}
+	}
+}
+
+func TestDecode_bad(t *testing.T) {
+	corrupt := []func(x *Index){
+		func(x *Index) { x.links[3][0][0] = 1000 },
+		func(x *Index) { x.links[3][0][0] = -1 },
+		func(x *Index) { x.links[x.entry] = x.links[x.entry][:1] },
+	}
+	for i, f := range corrupt {
+		x := New(4, Euclidean, 4, 20)
+		for _, v := range randomVectors(50, 4) {
+			x.Add(v)
+		}
+		f(x)
+		buf := &bytes.Buffer{}
+		if err := x.Encode(buf); err != nil {
+			t.Fatalf("Encode(...) failed: %v", err)
+		}
+		if err := (&Index{}).Decode(bufio.NewReader(buf)); err == nil {
+			t.Errorf("Decode(corruption #%d) succeeded, want error", i)
+		}
+	}
+}
+
+func TestDecode_badLength(t *testing.T) {
+	lengths := []uint64{1 << 62, 1 << 40, 3}
+	for _, n := range lengths {
+		buf := &bytes.Buffer{}
+		// dim, metric, M, efConstruction, efSearch, entry, n.
+		bnry.Write(buf, 4, int(Euclidean), 4, 20, 20, 0, 1)
+		bnry.Write(buf, n) // Length of the first vector.
+		if err := (&Index{}).Decode(bufio.NewReader(buf)); err == nil {
+			t.Errorf("Decode(vector length %d) succeeded, want error", n)
+		}
+
+		buf = &bytes.Buffer{}
+		bnry.Write(buf, 4, int(Euclidean), 4, 20, 20, 0, 1)
+		bnry.Write(buf, []float32{1, 2, 3, 4}, 1)
+		bnry.Write(buf, n) // Length of the first link list.
+		if err := (&Index{}).Decode(bufio.NewReader(buf)); err == nil {
+			t.Errorf("Decode(links length %d) succeeded, want error", n)
+		}
+	}
+}
+
+// Returns the IDs of the k nearest vectors to q by exhaustive search.
+func bruteForce(x *Index, q []float32, k int) []int {
+	q = x.prepare(q)
+	ids := make([]int, x.Len())
+	for i := range ids {
+		ids[i] = i
+	}
+	slices.SortFunc(ids, func(a, b int) int {
+		return cmp.Compare(x.dist(q, x.vecs[a]), x.dist(q, x.vecs[b]))
+	})
+	return ids[:k]
+}
+
+// Returns n random vectors of dimension dim.
+func randomVectors(n, dim int) [][]float32 {
+	result := make([][]float32, n)
+	for i := range result {
+		result[i] = make([]float32, dim)
+		for j := range result[i] {
+			result[i][j] = rand.Float32()*2 - 1
+		}
+	}
+	return result
+}
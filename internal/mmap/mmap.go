@@ -0,0 +1,17 @@
+// Package mmap provides read-only memory mapping of files.
+//
+// On platforms without mmap support, files are read into memory instead.
+package mmap
+
+// Map maps the given file to memory for reading.
+// The returned slice must not be modified, and must be released with
+// [Unmap] when no longer used.
+func Map(file string) ([]byte, error) {
+	return mapFile(file)
+}
+
+// Unmap releases a slice returned by [Map].
+// The slice must not be used after calling Unmap.
+func Unmap(b []byte) error {
+	return unmap(b)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package mmap
+
+import "os"
+
+func mapFile(file string) ([]byte, error) {
+	return os.ReadFile(file)
+}
+
+func unmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package mmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	for _, want := range []string{"", "a", "hello world"} {
+		file := filepath.Join(t.TempDir(), "data")
+		if err := os.WriteFile(file, []byte(want), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		b, err := Map(file)
+		if err != nil {
+			t.Fatalf("Map(%q) failed: %v", want, err)
+		}
+		if string(b) != want {
+			t.Errorf("Map(%q)=%q, want %q", want, b, want)
+		}
+		if err := Unmap(b); err != nil {
+			t.Errorf("Unmap(%q) failed: %v", want, err)
+		}
+	}
+}
+
+func TestMapNotExist(t *testing.T) {
+	if _, err := Map(filepath.Join(t.TempDir(), "nothing")); err == nil {
+		t.Errorf("Map(nonexistent) succeeded, want error")
+	}
+}
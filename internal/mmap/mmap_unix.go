@@ -0,0 +1,41 @@
+//go:build unix
+
+package mmap
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+func mapFile(file string) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	n := stat.Size()
+	if n == 0 {
+		return []byte{}, nil
+	}
+	if int64(int(n)) != n {
+		return nil, fmt.Errorf("file too large to map: %d bytes", n)
+	}
+	b, err := syscall.Mmap(int(f.Fd()), 0, int(n),
+		syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %s: %w", file, err)
+	}
+	return b, nil
+}
+
+func unmap(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return syscall.Munmap(b)
+}
@@ -0,0 +1,151 @@
+// Package sketchfmt implements the binary header shared by serialized
+// sketches.
+//
+// A serialized sketch is a fixed-size header followed by the sketch's raw
+// data. All header fields are little-endian:
+//
+//	offset  size  field
+//	0       4     magic "GSSK"
+//	4       1     format version
+//	5       1     sketch kind
+//	6       2     reserved (zero)
+//	8       4     first parameter
+//	12      4     second parameter
+//	16      8     data length in bytes
+//	24      8     reserved (zero)
+//
+// Because the header size is a multiple of 8, the data of a memory-mapped
+// sketch is 8-byte aligned.
+package sketchfmt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// Size is the size of a header in bytes.
+	Size = 32
+
+	// Version is the current format version.
+	Version = 1
+
+	magic = "GSSK"
+)
+
+// Sketch kinds.
+const (
+	KindBloom byte = 1
+	KindHLL   byte = 2
+)
+
+// Header holds the metadata of a serialized sketch.
+type Header struct {
+	Kind byte   // Sketch kind.
+	P1   uint32 // Kind-specific parameter.
+	P2   uint32 // Kind-specific parameter.
+	N    uint64 // Data length in bytes.
+}
+
+// Bytes returns the binary representation of the header.
+func (h Header) Bytes() []byte {
+	b := make([]byte, Size)
+	copy(b, magic)
+	b[4] = Version
+	b[5] = h.Kind
+	binary.LittleEndian.PutUint32(b[8:], h.P1)
+	binary.LittleEndian.PutUint32(b[12:], h.P2)
+	binary.LittleEndian.PutUint64(b[16:], h.N)
+	return b
+}
+
+// Parse decodes a header from the beginning of b, and checks that it is
+// of the given kind.
+func Parse(b []byte, kind byte) (Header, error) {
+	if len(b) < Size {
+		return Header{}, fmt.Errorf("header too short: %d bytes, want %d",
+			len(b), Size)
+	}
+	if string(b[:4]) != magic {
+		return Header{}, fmt.Errorf("bad magic: %q, want %q", b[:4], magic)
+	}
+	if b[4] != Version {
+		return Header{}, fmt.Errorf("unsupported version: %d, want %d",
+			b[4], Version)
+	}
+	if b[5] != kind {
+		return Header{}, fmt.Errorf("bad sketch kind: %d, want %d", b[5], kind)
+	}
+	return Header{
+		Kind: kind,
+		P1:   binary.LittleEndian.Uint32(b[8:]),
+		P2:   binary.LittleEndian.Uint32(b[12:]),
+		N:    binary.LittleEndian.Uint64(b[16:]),
+	}, nil
+}
+
+// Read reads a header from r, and checks that it is of the given kind.
+func Read(r io.Reader, kind byte) (Header, error) {
+	b := make([]byte, Size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return Header{}, err
+	}
+	return Parse(b, kind)
+}
+
+// Write writes a header followed by data to w.
+// Returns the number of bytes written.
+func Write(w io.Writer, h Header, data []byte) (int64, error) {
+	n, err := w.Write(h.Bytes())
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(data)
+	return int64(n + m), err
+}
+
+// ReadData reads a header and the data that follows it from r.
+// check validates the header before the data is read, and may be nil.
+// The data buffer grows as data arrives, so a corrupt length does not
+// cause a large allocation up front.
+// Returns the header, the data and the number of bytes read.
+func ReadData(r io.Reader, kind byte, check func(Header) error,
+) (Header, []byte, int64, error) {
+	h, err := Read(r, kind)
+	if err != nil {
+		return Header{}, nil, 0, err
+	}
+	if int64(h.N) < 0 || uint64(int(h.N)) != h.N {
+		return Header{}, nil, Size, fmt.Errorf("bad data length: %d", h.N)
+	}
+	if check != nil {
+		if err := check(h); err != nil {
+			return Header{}, nil, Size, err
+		}
+	}
+	buf := &bytes.Buffer{}
+	n, err := io.CopyN(buf, r, int64(h.N))
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Header{}, nil, Size + n, err
+	}
+	return h, buf.Bytes(), Size + n, nil
+}
+
+// Map parses a header and returns it along with the data that follows it
+// in b, without copying.
+func Map(b []byte, kind byte) (Header, []byte, error) {
+	h, err := Parse(b, kind)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if uint64(len(b)-Size) != h.N {
+		return Header{}, nil, fmt.Errorf("data length is %d, header says %d",
+			len(b)-Size, h.N)
+	}
+	return h, b[Size:], nil
+}
@@ -0,0 +1,260 @@
+// Package intervaltree provides an interval tree for overlap queries.
+//
+// The tree holds half-open intervals [start,end) along with associated
+// values. It is implemented as an AVL tree ordered by interval start,
+// where each node is augmented with the maximal end in its subtree.
+// Add and Delete are O(log n), and queries are O(log n + k) where k is the
+// number of reported intervals.
+package intervaltree
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// An Interval is a half-open interval [Start,End) with an associated value.
+type Interval[N cmp.Ordered, V any] struct {
+	Start N
+	End   N
+	Value V
+}
+
+// A Tree is an interval tree.
+// The zero value is an empty tree ready for use.
+type Tree[N cmp.Ordered, V comparable] struct {
+	root *node[N, V]
+	n    int
+}
+
+// A single node in the tree.
+type node[N cmp.Ordered, V comparable] struct {
+	iv     Interval[N, V]
+	maxEnd N // Maximal end in this subtree.
+	height int
+	left   *node[N, V]
+	right  *node[N, V]
+}
+
+// New returns an empty tree.
+func New[N cmp.Ordered, V comparable]() *Tree[N, V] {
+	return &Tree[N, V]{}
+}
+
+// Len returns the number of intervals in the tree.
+func (t *Tree[N, V]) Len() int {
+	return t.n
+}
+
+// Add inserts the interval [start,end) with value v.
+// Identical intervals may be added more than once.
+// Panics if end < start.
+func (t *Tree[N, V]) Add(start, end N, v V) {
+	if end < start {
+		panic(fmt.Sprintf("bad interval: end (%v) < start (%v)", end, start))
+	}
+	t.root = t.root.add(Interval[N, V]{start, end, v})
+	t.n++
+}
+
+// Delete removes a single instance of the interval [start,end) with value v.
+// Returns whether such an interval was found.
+func (t *Tree[N, V]) Delete(start, end N, v V) bool {
+	var ok bool
+	t.root, ok = t.root.delete(Interval[N, V]{start, end, v})
+	if ok {
+		t.n--
+	}
+	return ok
+}
+
+// Stab iterates over the intervals that contain x,
+// ordered by their start.
+func (t *Tree[N, V]) Stab(x N) iter.Seq[Interval[N, V]] {
+	return func(yield func(Interval[N, V]) bool) {
+		t.root.overlap(x, x, true, yield)
+	}
+}
+
+// Overlap iterates over the intervals that overlap [start,end),
+// ordered by their start.
+func (t *Tree[N, V]) Overlap(start, end N) iter.Seq[Interval[N, V]] {
+	return func(yield func(Interval[N, V]) bool) {
+		t.root.overlap(start, end, false, yield)
+	}
+}
+
+// All iterates over the intervals in the tree, ordered by their start.
+func (t *Tree[N, V]) All() iter.Seq[Interval[N, V]] {
+	return func(yield func(Interval[N, V]) bool) {
+		t.root.all(yield)
+	}
+}
+
+// Yields all intervals in the subtree in order.
+// Returns false if yield returned false.
+func (n *node[N, V]) all(yield func(Interval[N, V]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.all(yield) && yield(n.iv) && n.right.all(yield)
+}
+
+// Yields the intervals in the subtree that overlap [start,end),
+// or contain start if stab is true.
+// Returns false if yield returned false.
+func (n *node[N, V]) overlap(start, end N, stab bool,
+	yield func(Interval[N, V]) bool) bool {
+	if n == nil || n.maxEnd <= start {
+		return true
+	}
+	if !n.left.overlap(start, end, stab, yield) {
+		return false
+	}
+	if stab {
+		if n.iv.Start > start {
+			return true
+		}
+		if start < n.iv.End && !yield(n.iv) {
+			return false
+		}
+	} else {
+		if n.iv.Start >= end {
+			return true
+		}
+		if start < n.iv.End && !yield(n.iv) {
+			return false
+		}
+	}
+	return n.right.overlap(start, end, stab, yield)
+}
+
+// Compares intervals by start and then by end.
+func compare[N cmp.Ordered, V any](a, b Interval[N, V]) int {
+	if c := cmp.Compare(a.Start, b.Start); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.End, b.End)
+}
+
+// Inserts iv to the subtree and returns the new subtree root.
+func (n *node[N, V]) add(iv Interval[N, V]) *node[N, V] {
+	if n == nil {
+		return &node[N, V]{iv: iv, maxEnd: iv.End, height: 1}
+	}
+	if compare(iv, n.iv) < 0 {
+		n.left = n.left.add(iv)
+	} else {
+		n.right = n.right.add(iv)
+	}
+	return n.balance()
+}
+
+// Removes iv from the subtree and returns the new subtree root and whether
+// iv was found.
+func (n *node[N, V]) delete(iv Interval[N, V]) (*node[N, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var ok bool
+	switch c := compare(iv, n.iv); {
+	case c < 0:
+		n.left, ok = n.left.delete(iv)
+	case c > 0:
+		n.right, ok = n.right.delete(iv)
+	case iv.Value == n.iv.Value:
+		return n.deleteRoot(), true
+	default:
+		// Equal intervals may reside on both sides after rotations.
+		n.left, ok = n.left.delete(iv)
+		if !ok {
+			n.right, ok = n.right.delete(iv)
+		}
+	}
+	if !ok {
+		return n, false
+	}
+	return n.balance(), true
+}
+
+// Removes this node from its subtree and returns the new subtree root.
+func (n *node[N, V]) deleteRoot() *node[N, V] {
+	if n.left == nil {
+		return n.right
+	}
+	if n.right == nil {
+		return n.left
+	}
+	// Replace with the minimal node on the right.
+	var m *node[N, V]
+	n.right, m = n.right.popMin()
+	m.left, m.right = n.left, n.right
+	return m.balance()
+}
+
+// Removes the minimal node from the subtree and returns the new subtree root
+// and the removed node.
+func (n *node[N, V]) popMin() (*node[N, V], *node[N, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var m *node[N, V]
+	n.left, m = n.left.popMin()
+	return n.balance(), m
+}
+
+// Returns the height of the subtree, 0 for nil.
+func (n *node[N, V]) h() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Recalculates height and max end from the children.
+func (n *node[N, V]) update() {
+	n.height = max(n.left.h(), n.right.h()) + 1
+	n.maxEnd = n.iv.End
+	if n.left != nil {
+		n.maxEnd = max(n.maxEnd, n.left.maxEnd)
+	}
+	if n.right != nil {
+		n.maxEnd = max(n.maxEnd, n.right.maxEnd)
+	}
+}
+
+// Restores the AVL invariant at this node and returns the new subtree root.
+func (n *node[N, V]) balance() *node[N, V] {
+	n.update()
+	switch bf := n.left.h() - n.right.h(); {
+	case bf > 1:
+		if n.left.left.h() < n.left.right.h() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case bf < -1:
+		if n.right.right.h() < n.right.left.h() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *node[N, V]) rotateLeft() *node[N, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *node[N, V]) rotateRight() *node[N, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
@@ -0,0 +1,124 @@
+package intervaltree
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestStab(t *testing.T) {
+	tr := New[int, string]()
+	tr.Add(1, 5, "a")
+	tr.Add(3, 8, "b")
+	tr.Add(6, 7, "c")
+	tr.Add(10, 12, "d")
+
+	tests := []struct {
+		x    int
+		want []string
+	}{
+		{0, nil}, {1, []string{"a"}}, {3, []string{"a", "b"}},
+		{5, []string{"b"}}, {6, []string{"b", "c"}}, {7, []string{"b"}},
+		{8, nil}, {11, []string{"d"}}, {12, nil},
+	}
+	for _, test := range tests {
+		got := values(tr.Stab(test.x))
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Stab(%v)=%v, want %v", test.x, got, test.want)
+		}
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	tr := New[float64, int]()
+	tr.Add(1, 5, 1)
+	tr.Add(3, 8, 2)
+	tr.Add(6, 7, 3)
+	tr.Add(10, 12, 4)
+
+	tests := []struct {
+		start, end float64
+		want       []int
+	}{
+		{0, 1, nil}, {0, 1.5, []int{1}}, {5, 6, []int{2}},
+		{4, 6.5, []int{1, 2, 3}}, {8, 10, nil}, {7.5, 20, []int{2, 4}},
+	}
+	for _, test := range tests {
+		got := values(tr.Overlap(test.start, test.end))
+		if !slices.Equal(got, test.want) {
+			t.Errorf("Overlap(%v,%v)=%v, want %v",
+				test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tr := New[int, string]()
+	tr.Add(1, 5, "a")
+	tr.Add(1, 5, "b")
+	tr.Add(2, 3, "c")
+	if tr.Delete(1, 5, "c") {
+		t.Fatalf("Delete(1,5,c)=true, want false")
+	}
+	if !tr.Delete(1, 5, "a") {
+		t.Fatalf("Delete(1,5,a)=false, want true")
+	}
+	if got, want := values(tr.All()), []string{"b", "c"}; !slices.Equal(
+		got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+	if tr.Len() != 2 {
+		t.Fatalf("Len()=%v, want 2", tr.Len())
+	}
+}
+
+func TestRandom(t *testing.T) {
+	type iv = Interval[int, int]
+	tr := New[int, int]()
+	var all []iv
+	for i := range 1000 {
+		s := rand.IntN(1000)
+		e := s + rand.IntN(50)
+		tr.Add(s, e, i)
+		all = append(all, iv{s, e, i})
+	}
+	for i := 0; i < len(all); i += 2 {
+		if !tr.Delete(all[i].Start, all[i].End, all[i].Value) {
+			t.Fatalf("Delete(%v)=false, want true", all[i])
+		}
+		all[i].Value = -1
+	}
+	all = slices.DeleteFunc(all, func(x iv) bool { return x.Value == -1 })
+	if tr.Len() != len(all) {
+		t.Fatalf("Len()=%v, want %v", tr.Len(), len(all))
+	}
+	if h, n := tr.root.h(), tr.Len(); 1<<(h/2) > n {
+		t.Fatalf("height=%v is too high for %v elements", h, n)
+	}
+
+	for range 100 {
+		s := rand.IntN(1000)
+		e := s + rand.IntN(50)
+		var want []int
+		for _, x := range all {
+			if x.Start < e && s < x.End {
+				want = append(want, x.Value)
+			}
+		}
+		got := values(tr.Overlap(s, e))
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Overlap(%v,%v)=%v, want %v", s, e, got, want)
+		}
+	}
+}
+
+// Collects the values of the given intervals.
+func values[N int | float64, V any](it func(func(Interval[N, V]) bool)) []V {
+	var result []V
+	for x := range it {
+		result = append(result, x.Value)
+	}
+	return result
+}
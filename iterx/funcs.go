@@ -0,0 +1,143 @@
+package iterx
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Map returns an iterator over the results of f on the elements of it.
+func Map[T, S any](it iter.Seq[T], f func(T) S) iter.Seq[S] {
+	return func(yield func(S) bool) {
+		for x := range it {
+			if !yield(f(x)) {
+				return
+			}
+		}
+	}
+}
+
+// Map2 returns an iterator over the results of f on the pairs of it.
+func Map2[T, S, T2, S2 any](it iter.Seq2[T, S],
+	f func(T, S) (T2, S2)) iter.Seq2[T2, S2] {
+	return func(yield func(T2, S2) bool) {
+		for x, y := range it {
+			if !yield(f(x, y)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the elements of it for which keep
+// returns true.
+func Filter[T any](it iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := range it {
+			if keep(x) && !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Filter2 returns an iterator over the pairs of it for which keep
+// returns true.
+func Filter2[T, S any](it iter.Seq2[T, S],
+	keep func(T, S) bool) iter.Seq2[T, S] {
+	return func(yield func(T, S) bool) {
+		for x, y := range it {
+			if keep(x, y) && !yield(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator over consecutive slices of up to n elements
+// of it. Each yielded slice is newly allocated.
+// Panics if n is less than 1.
+func Chunk[T any](it iter.Seq[T], n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for x := range it {
+			if chunk == nil {
+				chunk = make([]T, 0, n)
+			}
+			chunk = append(chunk, x)
+			if len(chunk) == n {
+				if !yield(chunk) {
+					return
+				}
+				chunk = nil
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Zip returns an iterator over pairs of elements from a and b.
+// Stops when either iterator stops.
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for x := range a {
+			y, ok := next()
+			if !ok || !yield(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over the elements of it and their
+// 0-based indexes, like in a range expression over a slice.
+func Enumerate[T any](it iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for x := range it {
+			if !yield(i, x) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Reduce applies f cumulatively on the elements of it, starting with init,
+// and returns the final value.
+func Reduce[T, A any](it iter.Seq[T], init A, f func(A, T) A) A {
+	acc := init
+	for x := range it {
+		acc = f(acc, x)
+	}
+	return acc
+}
+
+// Collect2 returns the keys and values of it as two slices.
+func Collect2[T, S any](it iter.Seq2[T, S]) ([]T, []S) {
+	var ts []T
+	var ss []S
+	for x, y := range it {
+		ts = append(ts, x)
+		ss = append(ss, y)
+	}
+	return ts, ss
+}
+
+// Take returns an iterator over the first n elements of it.
+// It is the same as [Limit], named to pair with [Skip].
+func Take[T any](it iter.Seq[T], n int) iter.Seq[T] {
+	return Limit(it, n)
+}
+
+// Take2 returns an iterator over the first n pairs of it.
+// It is the same as [Limit2], named to pair with [Skip2].
+func Take2[T, S any](it iter.Seq2[T, S], n int) iter.Seq2[T, S] {
+	return Limit2(it, n)
+}
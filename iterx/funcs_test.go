@@ -0,0 +1,110 @@
+package iterx
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	input := []int{1, 2, 3}
+	got := slices.Collect(Map(slices.Values(input), strconv.Itoa))
+	if want := []string{"1", "2", "3"}; !slices.Equal(got, want) {
+		t.Errorf("Map(%v)=%v, want %v", input, got, want)
+	}
+}
+
+func TestMap2(t *testing.T) {
+	input := []string{"a", "b"}
+	got := maps.Collect(Map2(slices.All(input), func(i int, s string) (string, int) {
+		return s, i * 10
+	}))
+	want := map[string]int{"a": 0, "b": 10}
+	if !maps.Equal(got, want) {
+		t.Errorf("Map2(%v)=%v, want %v", input, got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	got := slices.Collect(Filter(slices.Values(input), func(i int) bool {
+		return i%2 == 1
+	}))
+	if want := []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("Filter(%v)=%v, want %v", input, got, want)
+	}
+	_, got2 := Collect2(Filter2(slices.All(input), func(i, x int) bool {
+		return i >= 3
+	}))
+	if want := []int{4, 5}; !slices.Equal(got2, want) {
+		t.Errorf("Filter2(%v)=%v, want %v", input, got2, want)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		n    int
+		want [][]int
+	}{
+		{1, [][]int{{1}, {2}, {3}, {4}, {5}}},
+		{2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{5, [][]int{{1, 2, 3, 4, 5}}},
+		{6, [][]int{{1, 2, 3, 4, 5}}},
+	}
+	for _, test := range tests {
+		got := slices.Collect(Chunk(slices.Values(input), test.n))
+		if !slices.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("Chunk(%v,%v)=%v, want %v", input, test.n, got, test.want)
+		}
+	}
+	if got := slices.Collect(Chunk(slices.Values([]int{}), 3)); len(got) != 0 {
+		t.Errorf("Chunk([],3)=%v, want []", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := []string{"a", "b"}
+	gota, gotb := Collect2(Zip(slices.Values(a), slices.Values(b)))
+	if want := []int{1, 2}; !slices.Equal(gota, want) {
+		t.Errorf("Zip(%v,%v) keys=%v, want %v", a, b, gota, want)
+	}
+	if !slices.Equal(gotb, b) {
+		t.Errorf("Zip(%v,%v) values=%v, want %v", a, b, gotb, b)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	input := []string{"a", "b", "c"}
+	i, s := Collect2(Enumerate(slices.Values(input)))
+	if want := []int{0, 1, 2}; !slices.Equal(i, want) {
+		t.Errorf("Enumerate(%v) indexes=%v, want %v", input, i, want)
+	}
+	if !slices.Equal(s, input) {
+		t.Errorf("Enumerate(%v) values=%v, want %v", input, s, input)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	got := Reduce(slices.Values(input), "", func(a string, i int) string {
+		return a + strconv.Itoa(i)
+	})
+	if want := "1234"; got != want {
+		t.Errorf("Reduce(%v)=%q, want %q", input, got, want)
+	}
+}
+
+func TestTake(t *testing.T) {
+	input := []int{1, 2, 3, 4, 5}
+	got := slices.Collect(Take(slices.Values(input), 2))
+	if want := []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("Take(%v, 2)=%v, want %v", input, got, want)
+	}
+	got = slices.Collect(Skip(slices.Values(input), 2))
+	if want := []int{3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Skip(%v, 2)=%v, want %v", input, got, want)
+	}
+}
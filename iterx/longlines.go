@@ -0,0 +1,79 @@
+package iterx
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"iter"
+
+	"github.com/fluhus/gostuff/aio"
+)
+
+// A Line is a text line with its position in the input.
+type Line struct {
+	Text   string // Without the line terminator
+	Num    int    // 1-based line number
+	Offset int64  // Byte offset of the line's start in the input
+}
+
+// LongLinesReader iterates over text lines from a reader.
+// Unlike [LinesReader], there is no limit on line length.
+// Lines are terminated by "\n" or "\r\n", and the terminator is
+// not included in the text.
+func LongLinesReader(r io.Reader) iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		br := bufio.NewReader(r)
+		var buf []byte
+		line := Line{Num: 1}
+		for {
+			b, err := br.ReadSlice('\n')
+			if err == bufio.ErrBufferFull {
+				buf = append(buf, b...)
+				continue
+			}
+			if err != nil && err != io.EOF {
+				yield(Line{}, err)
+				return
+			}
+			if len(buf) > 0 {
+				b = append(buf, b...)
+				buf = buf[:0]
+			}
+			if len(b) == 0 { // EOF after a terminated line.
+				return
+			}
+			n := len(b)
+			b = bytes.TrimSuffix(b, []byte("\n"))
+			b = bytes.TrimSuffix(b, []byte("\r"))
+			line.Text = string(b)
+			if !yield(line, nil) {
+				return
+			}
+			if err == io.EOF {
+				return
+			}
+			line.Num++
+			line.Offset += int64(n)
+		}
+	}
+}
+
+// LongLinesFile iterates over text lines from a file.
+// Unlike [LinesFile], there is no limit on line length.
+// Lines are terminated by "\n" or "\r\n", and the terminator is
+// not included in the text.
+func LongLinesFile(file string) iter.Seq2[Line, error] {
+	return func(yield func(Line, error) bool) {
+		f, err := aio.Open(file)
+		if err != nil {
+			yield(Line{}, err)
+			return
+		}
+		defer f.Close()
+		for line, err := range LongLinesReader(f) {
+			if !yield(line, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package iterx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLongLinesReader(t *testing.T) {
+	long := strings.Repeat("a", 200000)
+	tests := []struct {
+		input string
+		want  []Line
+	}{
+		{"", nil},
+		{"a", []Line{{"a", 1, 0}}},
+		{"a\n", []Line{{"a", 1, 0}}},
+		{"a\r\nbb\n\nccc", []Line{
+			{"a", 1, 0}, {"bb", 2, 3}, {"", 3, 6}, {"ccc", 4, 7}}},
+		{long + "\n" + long + "\r\nb\n", []Line{
+			{long, 1, 0}, {long, 2, 200001}, {"b", 3, 400003}}},
+	}
+	for _, test := range tests {
+		var got []Line
+		for line, err := range LongLinesReader(strings.NewReader(test.input)) {
+			if err != nil {
+				t.Fatalf("LongLinesReader(%.10q) failed: %v", test.input, err)
+			}
+			got = append(got, line)
+		}
+		if len(got) != len(test.want) {
+			t.Fatalf("LongLinesReader(%.10q) got %d lines, want %d",
+				test.input, len(got), len(test.want))
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("LongLinesReader(%.10q)[%d]=%.20v, want %.20v",
+					test.input, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestLongLinesReader_break(t *testing.T) {
+	n := 0
+	for range LongLinesReader(strings.NewReader("a\nb\nc\n")) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Fatalf("LongLinesReader(...) yielded %d lines after break, want 2", n)
+	}
+}
@@ -12,13 +12,22 @@ import (
 )
 
 // Write saves v to the given file, encoded as JSON.
+// Compresses the data according to the file's suffix.
 func Write(file string, v any) error {
+	return WriteIndent(file, v, "  ")
+}
+
+// WriteIndent saves v to the given file, encoded as JSON with the given
+// indentation per nesting level. An empty indent produces compact JSON
+// on a single line.
+// Compresses the data according to the file's suffix.
+func WriteIndent(file string, v any, indent string) error {
 	f, err := aio.Create(file)
 	if err != nil {
 		return err
 	}
 	e := json.NewEncoder(f)
-	e.SetIndent("", "  ")
+	e.SetIndent("", indent)
 	if err := e.Encode(v); err != nil {
 		f.Close()
 		return err
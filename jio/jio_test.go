@@ -0,0 +1,54 @@
+package jio
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	type data struct {
+		A int
+		B []string
+	}
+	want := data{3, []string{"x", "y"}}
+	dir := t.TempDir()
+	for _, name := range []string{"a.json", "a.json.gz"} {
+		file := filepath.Join(dir, name)
+		if err := Write(file, want); err != nil {
+			t.Fatalf("Write(%q) failed: %v", name, err)
+		}
+		got, err := ReadAs[data](file)
+		if err != nil {
+			t.Fatalf("ReadAs(%q) failed: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("ReadAs(%q)=%v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestWriteIndent(t *testing.T) {
+	tests := []struct {
+		indent string
+		want   string
+	}{
+		{"", "{\"a\":[1,2]}\n"},
+		{"\t", "{\n\t\"a\": [\n\t\t1,\n\t\t2\n\t]\n}\n"},
+	}
+	for _, test := range tests {
+		file := filepath.Join(t.TempDir(), "a.json")
+		v := map[string][]int{"a": {1, 2}}
+		if err := WriteIndent(file, v, test.indent); err != nil {
+			t.Fatalf("WriteIndent(%q) failed: %v", test.indent, err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != test.want {
+			t.Errorf("WriteIndent(%q)=%q, want %q", test.indent, got, test.want)
+		}
+	}
+}
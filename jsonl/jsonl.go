@@ -0,0 +1,159 @@
+// Package jsonl provides streaming of JSON Lines data, where each line
+// holds a single JSON value.
+//
+// Uses the [aio] package for file I/O, so files are compressed and
+// decompressed according to their suffix.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/fluhus/gostuff/aio"
+)
+
+// A Reader decodes values of type T from JSON lines.
+// Lines have no length limit. Empty lines are ignored.
+type Reader[T any] struct {
+	r *bufio.Reader
+
+	// If true, lines that fail to decode are skipped instead of
+	// returning an error.
+	SkipInvalid bool
+
+	line    int
+	skipped int
+}
+
+// NewReader returns a reader that reads from r.
+func NewReader[T any](r io.Reader) *Reader[T] {
+	return &Reader[T]{r: bufio.NewReader(r)}
+}
+
+// Read returns the next value. Returns io.EOF at the end of the input.
+// Decoding errors include the line number.
+func (r *Reader[T]) Read() (T, error) {
+	var t T
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return t, err
+		}
+		if err != nil && err != io.EOF {
+			return t, err
+		}
+		r.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if jerr := json.Unmarshal(line, &t); jerr != nil {
+			if r.SkipInvalid {
+				r.skipped++
+				var zero T
+				t = zero
+				continue
+			}
+			return t, fmt.Errorf("line %d: %w", r.line, jerr)
+		}
+		return t, nil
+	}
+}
+
+// All returns an iterator over the remaining values.
+// Iteration stops after the first error.
+func (r *Reader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			t, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+			if !yield(t, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Skipped returns the number of invalid lines that were skipped.
+func (r *Reader[T]) Skipped() int {
+	return r.skipped
+}
+
+// ReadFile returns an iterator over the values in the given file.
+// If skipInvalid is true, lines that fail to decode are skipped.
+func ReadFile[T any](file string, skipInvalid bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		f, err := aio.Open(file)
+		if err != nil {
+			var t T
+			yield(t, err)
+			return
+		}
+		defer f.Close()
+		r := NewReader[T](f)
+		r.SkipInvalid = skipInvalid
+		for t, err := range r.All() {
+			if !yield(t, err) {
+				return
+			}
+		}
+	}
+}
+
+// A Writer encodes values of type T as JSON lines.
+type Writer[T any] struct {
+	c io.Closer // Nil if the writer does not own its output.
+	e *json.Encoder
+}
+
+// NewWriter returns a writer that writes to w.
+func NewWriter[T any](w io.Writer) *Writer[T] {
+	return &Writer[T]{e: json.NewEncoder(w)}
+}
+
+// Create returns a writer that writes to the given file.
+// The writer must be closed to flush the data.
+func Create[T any](file string) (*Writer[T], error) {
+	f, err := aio.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	w := NewWriter[T](f)
+	w.c = f
+	return w, nil
+}
+
+// Write writes t as a single line.
+func (w *Writer[T]) Write(t T) error {
+	return w.e.Encode(t)
+}
+
+// Close closes the underlying file if the writer was created with Create.
+// Otherwise does nothing.
+func (w *Writer[T]) Close() error {
+	if w.c == nil {
+		return nil
+	}
+	return w.c.Close()
+}
+
+// WriteFile writes the given values to a file, one per line.
+func WriteFile[T any](file string, values iter.Seq[T]) error {
+	w, err := Create[T](file)
+	if err != nil {
+		return err
+	}
+	for t := range values {
+		if err := w.Write(t); err != nil {
+			w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
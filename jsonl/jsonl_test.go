@@ -0,0 +1,100 @@
+package jsonl
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	A int
+	B string
+}
+
+func TestReadWrite(t *testing.T) {
+	want := []item{{1, "a"}, {2, "b\nc"}, {3, ""}}
+	buf := bytes.NewBuffer(nil)
+	w := NewWriter[item](buf)
+	for _, x := range want {
+		if err := w.Write(x); err != nil {
+			t.Fatalf("Write(%v) failed: %v", x, err)
+		}
+	}
+	if n := strings.Count(buf.String(), "\n"); n != len(want) {
+		t.Fatalf("wrote %v lines, want %v", n, len(want))
+	}
+	var got []item
+	for x, err := range NewReader[item](buf).All() {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		got = append(got, x)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	input := "{\"A\":1}\n\nnot json\n{\"A\":2}"
+	r := NewReader[item](strings.NewReader(input))
+	if _, err := r.Read(); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	_, err := r.Read()
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Fatalf("Read()=%v, want line 3 error", err)
+	}
+
+	r = NewReader[item](strings.NewReader(input))
+	r.SkipInvalid = true
+	var got []item
+	for x, err := range r.All() {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		got = append(got, x)
+	}
+	if want := []item{{A: 1}, {A: 2}}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+	if r.Skipped() != 1 {
+		t.Fatalf("Skipped()=%v, want 1", r.Skipped())
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Read()=%v, want EOF", err)
+	}
+}
+
+func TestLongLine(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	buf := bytes.NewBuffer(nil)
+	NewWriter[item](buf).Write(item{B: long})
+	got, err := NewReader[item](buf).Read()
+	if err != nil || got.B != long {
+		t.Fatalf("Read() failed: %v", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	want := []item{{1, "a"}, {2, "b"}}
+	for _, name := range []string{"a.jsonl", "a.jsonl.gz"} {
+		file := filepath.Join(t.TempDir(), name)
+		if err := WriteFile(file, slices.Values(want)); err != nil {
+			t.Fatalf("WriteFile(%q) failed: %v", name, err)
+		}
+		var got []item
+		for x, err := range ReadFile[item](file, false) {
+			if err != nil {
+				t.Fatalf("ReadFile(%q) failed: %v", name, err)
+			}
+			got = append(got, x)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ReadFile(%q)=%v, want %v", name, got, want)
+		}
+	}
+}
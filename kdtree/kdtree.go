@@ -0,0 +1,231 @@
+// Package kdtree provides a k-d tree for nearest-neighbor and range queries
+// over low-dimensional points.
+//
+// The tree works well for a small number of dimensions (up to about 10).
+// For higher dimensions, a linear scan is often as fast.
+package kdtree
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/fluhus/gostuff/gnum"
+	"github.com/fluhus/gostuff/heaps"
+)
+
+// A Tree is a k-d tree over points of a fixed dimension.
+// Points are not copied, and should not be modified after insertion.
+type Tree[N gnum.Number] struct {
+	root *node[N]
+	k    int
+	n    int
+}
+
+// A single node in the tree.
+type node[N gnum.Number] struct {
+	p           []N
+	left, right *node[N]
+}
+
+// New returns an empty tree over points of dimension k.
+func New[N gnum.Number](k int) *Tree[N] {
+	if k < 1 {
+		panic(fmt.Sprintf("bad k: %d", k))
+	}
+	return &Tree[N]{k: k}
+}
+
+// FromSlice returns a balanced tree over the given points, which must all
+// have dimension k.
+func FromSlice[S ~[]N, N gnum.Number](k int, points []S) *Tree[N] {
+	t := New[N](k)
+	pp := make([][]N, len(points))
+	for i, p := range points {
+		t.checkDim(p)
+		pp[i] = p
+	}
+	t.root = build(pp, 0, k)
+	t.n = len(pp)
+	return t
+}
+
+// Builds a balanced subtree by splitting on the median.
+func build[N gnum.Number](pp [][]N, depth, k int) *node[N] {
+	if len(pp) == 0 {
+		return nil
+	}
+	axis := depth % k
+	slices.SortFunc(pp, func(a, b []N) int {
+		switch {
+		case a[axis] < b[axis]:
+			return -1
+		case a[axis] > b[axis]:
+			return 1
+		}
+		return 0
+	})
+	m := len(pp) / 2
+	// Equal values go right, consistent with Insert.
+	for m > 0 && pp[m-1][axis] == pp[m][axis] {
+		m--
+	}
+	return &node[N]{
+		p:     pp[m],
+		left:  build(pp[:m], depth+1, k),
+		right: build(pp[m+1:], depth+1, k),
+	}
+}
+
+// Len returns the number of points in the tree.
+func (t *Tree[N]) Len() int {
+	return t.n
+}
+
+// Insert adds p to the tree.
+// Insertions do not rebalance the tree, so inserting many points in sorted
+// order may degrade performance. Use [FromSlice] for bulk loading.
+func (t *Tree[N]) Insert(p []N) {
+	t.checkDim(p)
+	t.n++
+	nu := &node[N]{p: p}
+	if t.root == nil {
+		t.root = nu
+		return
+	}
+	cur := t.root
+	for depth := 0; ; depth++ {
+		axis := depth % t.k
+		next := &cur.right
+		if p[axis] < cur.p[axis] {
+			next = &cur.left
+		}
+		if *next == nil {
+			*next = nu
+			return
+		}
+		cur = *next
+	}
+}
+
+// NearestN returns the n points closest to q by Euclidean distance,
+// ordered from closest to farthest.
+func (t *Tree[N]) NearestN(q []N, n int) [][]N {
+	t.checkDim(q)
+	if n < 1 {
+		return nil
+	}
+	type item struct {
+		p []N
+		d float64
+	}
+	// Max-heap of the best n found so far.
+	h := heaps.New(func(a, b item) bool { return a.d > b.d })
+	var search func(nd *node[N], depth int)
+	search = func(nd *node[N], depth int) {
+		if nd == nil {
+			return
+		}
+		d := dist2(q, nd.p)
+		if h.Len() < n {
+			h.Push(item{nd.p, d})
+		} else if d < h.Head().d {
+			h.Pop()
+			h.Push(item{nd.p, d})
+		}
+		axis := depth % t.k
+		diff := float64(q[axis]) - float64(nd.p[axis])
+		near, far := nd.left, nd.right
+		if diff >= 0 {
+			near, far = far, near
+		}
+		search(near, depth+1)
+		if h.Len() < n || diff*diff < h.Head().d {
+			search(far, depth+1)
+		}
+	}
+	search(t.root, 0)
+
+	result := make([][]N, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = h.Pop().p
+	}
+	return result
+}
+
+// Range iterates over the points p for which lo[i] <= p[i] <= hi[i]
+// in every dimension i.
+func (t *Tree[N]) Range(lo, hi []N) iter.Seq[[]N] {
+	t.checkDim(lo)
+	t.checkDim(hi)
+	return func(yield func([]N) bool) {
+		var search func(nd *node[N], depth int) bool
+		search = func(nd *node[N], depth int) bool {
+			if nd == nil {
+				return true
+			}
+			axis := depth % t.k
+			if lo[axis] < nd.p[axis] && !search(nd.left, depth+1) {
+				return false
+			}
+			if inBox(nd.p, lo, hi) && !yield(nd.p) {
+				return false
+			}
+			if hi[axis] >= nd.p[axis] && !search(nd.right, depth+1) {
+				return false
+			}
+			return true
+		}
+		search(t.root, 0)
+	}
+}
+
+// All iterates over the points in the tree, in no particular order.
+func (t *Tree[N]) All() iter.Seq[[]N] {
+	return func(yield func([]N) bool) {
+		stack := []*node[N]{}
+		if t.root != nil {
+			stack = append(stack, t.root)
+		}
+		for len(stack) > 0 {
+			nd := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(nd.p) {
+				return
+			}
+			if nd.left != nil {
+				stack = append(stack, nd.left)
+			}
+			if nd.right != nil {
+				stack = append(stack, nd.right)
+			}
+		}
+	}
+}
+
+// Returns the squared Euclidean distance between a and b.
+func dist2[N gnum.Number](a, b []N) float64 {
+	sum := 0.0
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return sum
+}
+
+// Returns whether p is inside the box defined by lo and hi.
+func inBox[N gnum.Number](p, lo, hi []N) bool {
+	for i := range p {
+		if p[i] < lo[i] || p[i] > hi[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Panics if p's dimension does not match the tree's.
+func (t *Tree[N]) checkDim(p []N) {
+	if len(p) != t.k {
+		panic(fmt.Sprintf("point has dimension %d, want %d", len(p), t.k))
+	}
+}
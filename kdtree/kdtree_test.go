@@ -0,0 +1,88 @@
+package kdtree
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestNearestN(t *testing.T) {
+	points := randomPoints(1000, 3)
+	trees := map[string]*Tree[float64]{
+		"Insert":    New[float64](3),
+		"FromSlice": FromSlice(3, slices.Clone(points)),
+	}
+	for _, p := range points {
+		trees["Insert"].Insert(p)
+	}
+	for name, tr := range trees {
+		if tr.Len() != len(points) {
+			t.Fatalf("%s: Len()=%v, want %v", name, tr.Len(), len(points))
+		}
+		for range 50 {
+			q := randomPoints(1, 3)[0]
+			want := slices.Clone(points)
+			slices.SortFunc(want, func(a, b []float64) int {
+				return cmp.Compare(dist2(a, q), dist2(b, q))
+			})
+			want = want[:5]
+			got := tr.NearestN(q, 5)
+			if !slices.EqualFunc(got, want, slices.Equal) {
+				t.Fatalf("%s: NearestN(%v,5)=%v, want %v",
+					name, q, got, want)
+			}
+		}
+	}
+}
+
+func TestNearestN_small(t *testing.T) {
+	tr := New[int](2)
+	tr.Insert([]int{1, 1})
+	tr.Insert([]int{5, 5})
+	got := tr.NearestN([]int{4, 4}, 10)
+	want := [][]int{{5, 5}, {1, 1}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("NearestN(...)=%v, want %v", got, want)
+	}
+}
+
+func TestRange(t *testing.T) {
+	points := randomPoints(1000, 2)
+	tr := FromSlice(2, slices.Clone(points))
+	lo, hi := []float64{0.2, 0.4}, []float64{0.5, 0.6}
+	var want [][]float64
+	for _, p := range points {
+		if inBox(p, lo, hi) {
+			want = append(want, p)
+		}
+	}
+	got := slices.Collect(tr.Range(lo, hi))
+	cmpPoints := func(a, b []float64) int { return slices.Compare(a, b) }
+	slices.SortFunc(want, cmpPoints)
+	slices.SortFunc(got, cmpPoints)
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Range(%v,%v)=%v, want %v", lo, hi, got, want)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	points := [][]int{{1, 1}, {1, 1}, {1, 2}, {1, 1}, {0, 1}}
+	tr := FromSlice(2, slices.Clone(points))
+	got := slices.Collect(tr.Range([]int{1, 1}, []int{1, 1}))
+	if len(got) != 3 {
+		t.Fatalf("Range({1,1},{1,1})=%v, want 3 points", got)
+	}
+}
+
+// Returns n random points of dimension k.
+func randomPoints(n, k int) [][]float64 {
+	result := make([][]float64, n)
+	for i := range result {
+		result[i] = make([]float64, k)
+		for j := range k {
+			result[i][j] = rand.Float64()
+		}
+	}
+	return result
+}
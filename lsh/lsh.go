@@ -0,0 +1,168 @@
+// Package lsh provides locality-sensitive hashing for dense vectors under
+// cosine similarity, using signed random projections.
+//
+// Each bit of a vector's signature is the sign of its dot product with a
+// random hyperplane. The probability that two vectors agree on a bit is
+// 1 - angle/pi, so the Hamming distance between signatures estimates the
+// angle between the vectors.
+//
+// An [Index] splits signatures into bands, and retrieves as candidates
+// the vectors that share at least one band with the query.
+package lsh
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/fluhus/gostuff/sets"
+	"github.com/fluhus/gostuff/snm"
+	"golang.org/x/exp/constraints"
+)
+
+// Hyperplanes hashes vectors to bit signatures using random hyperplanes.
+type Hyperplanes[N constraints.Float] struct {
+	planes [][]N
+}
+
+// NewHyperplanes returns nbits random hyperplanes over vectors of dimension
+// dim. Instances with the same parameters and seed produce the same
+// signatures.
+func NewHyperplanes[N constraints.Float](dim, nbits int, seed uint64,
+) *Hyperplanes[N] {
+	if dim < 1 {
+		panic(fmt.Sprintf("bad dim: %d", dim))
+	}
+	if nbits < 1 || nbits > 64 {
+		panic(fmt.Sprintf("bad nbits: %d, want 1-64", nbits))
+	}
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	planes := snm.Slice(nbits, func(int) []N {
+		return snm.Slice(dim, func(int) N { return N(rng.NormFloat64()) })
+	})
+	return &Hyperplanes[N]{planes}
+}
+
+// NBits returns the number of bits in a signature.
+func (h *Hyperplanes[N]) NBits() int {
+	return len(h.planes)
+}
+
+// Signature returns the bit signature of v.
+// Bit i is set if v is on the positive side of the i'th hyperplane.
+func (h *Hyperplanes[N]) Signature(v []N) uint64 {
+	if len(v) != len(h.planes[0]) {
+		panic(fmt.Sprintf("vector has dimension %d, want %d",
+			len(v), len(h.planes[0])))
+	}
+	var sig uint64
+	for i, p := range h.planes {
+		var dot N
+		for j := range v {
+			dot += v[j] * p[j]
+		}
+		if dot >= 0 {
+			sig |= 1 << i
+		}
+	}
+	return sig
+}
+
+// Hamming returns the number of bits that differ between a and b.
+func Hamming(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// EstimateCosine returns the cosine similarity estimated from the
+// signatures of two vectors.
+func EstimateCosine(a, b uint64, nbits int) float64 {
+	return math.Cos(math.Pi * float64(Hamming(a, b)) / float64(nbits))
+}
+
+// An Index retrieves candidate similar vectors by their signatures.
+type Index[N constraints.Float] struct {
+	h     *Hyperplanes[N]
+	rows  int                // Bits per band.
+	bands []map[uint64][]int // Bucket of each band value.
+	sigs  []uint64           // Signature of each added vector.
+}
+
+// A Result is a single search result.
+type Result struct {
+	ID      int // ID of the found vector, as returned by Add.
+	Hamming int // Hamming distance between the signatures.
+}
+
+// NewIndex returns an empty index over vectors of dimension dim.
+// Signatures have nbands*rowsPerBand bits, which must be at most 64.
+// More bands increase recall, more rows per band increase precision.
+func NewIndex[N constraints.Float](dim, nbands, rowsPerBand int,
+	seed uint64) *Index[N] {
+	if nbands < 1 || rowsPerBand < 1 || nbands*rowsPerBand > 64 {
+		panic(fmt.Sprintf("bad bands: %dx%d, want positive with product <=64",
+			nbands, rowsPerBand))
+	}
+	return &Index[N]{
+		h:     NewHyperplanes[N](dim, nbands*rowsPerBand, seed),
+		rows:  rowsPerBand,
+		bands: snm.Slice(nbands, func(int) map[uint64][]int { return map[uint64][]int{} }),
+	}
+}
+
+// Len returns the number of vectors in the index.
+func (x *Index[N]) Len() int {
+	return len(x.sigs)
+}
+
+// Add inserts v to the index and returns its ID.
+// IDs are sequential, starting at 0.
+func (x *Index[N]) Add(v []N) int {
+	id := len(x.sigs)
+	sig := x.h.Signature(v)
+	x.sigs = append(x.sigs, sig)
+	for i, b := range x.bands {
+		key := x.band(sig, i)
+		b[key] = append(b[key], id)
+	}
+	return id
+}
+
+// Signature returns the signature of the vector with the given ID.
+func (x *Index[N]) Signature(id int) uint64 {
+	return x.sigs[id]
+}
+
+// Candidates returns the IDs of the vectors that share at least one band
+// with v, in ascending order.
+func (x *Index[N]) Candidates(v []N) []int {
+	sig := x.h.Signature(v)
+	found := sets.Set[int]{}
+	for i, b := range x.bands {
+		found.Add(b[x.band(sig, i)]...)
+	}
+	return slices.Sorted(maps.Keys(found))
+}
+
+// Search returns the candidates for v whose signatures are within
+// maxHamming of v's signature, ordered by Hamming distance.
+func (x *Index[N]) Search(v []N, maxHamming int) []Result {
+	sig := x.h.Signature(v)
+	var result []Result
+	for _, id := range x.Candidates(v) {
+		if d := Hamming(sig, x.sigs[id]); d <= maxHamming {
+			result = append(result, Result{id, d})
+		}
+	}
+	slices.SortStableFunc(result, func(a, b Result) int {
+		return a.Hamming - b.Hamming
+	})
+	return result
+}
+
+// Returns the value of band i in sig.
+func (x *Index[N]) band(sig uint64, i int) uint64 {
+	return (sig >> (i * x.rows)) & (1<<x.rows - 1)
+}
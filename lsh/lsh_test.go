@@ -0,0 +1,119 @@
+package lsh
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/fluhus/gostuff/snm"
+)
+
+func TestSignatureDeterministic(t *testing.T) {
+	a := NewHyperplanes[float64](10, 64, 1)
+	b := NewHyperplanes[float64](10, 64, 1)
+	c := NewHyperplanes[float64](10, 64, 2)
+	v := snm.Slice(10, func(int) float64 { return rand.NormFloat64() })
+	if a.Signature(v) != b.Signature(v) {
+		t.Errorf("same seed gave different signatures")
+	}
+	if a.Signature(v) == c.Signature(v) {
+		t.Errorf("different seeds gave the same signature")
+	}
+}
+
+func TestSignatureOpposite(t *testing.T) {
+	h := NewHyperplanes[float32](5, 40, 3)
+	v := []float32{1, -2, 3, 0.5, -1}
+	u := snm.SliceToSlice(v, func(x float32) float32 { return -x })
+	if got := Hamming(h.Signature(v), h.Signature(u)); got != 40 {
+		t.Errorf("Hamming(v,-v)=%v, want 40", got)
+	}
+	if got := Hamming(h.Signature(v), h.Signature(v)); got != 0 {
+		t.Errorf("Hamming(v,v)=%v, want 0", got)
+	}
+}
+
+func TestEstimateCosine(t *testing.T) {
+	const dim = 20
+	h := NewHyperplanes[float64](dim, 64, 4)
+	sum := 0.0
+	const n = 100
+	for range n {
+		v := snm.Slice(dim, func(int) float64 { return rand.NormFloat64() })
+		u := snm.SliceToSlice(v, func(x float64) float64 {
+			return x + rand.NormFloat64()*0.5
+		})
+		got := EstimateCosine(h.Signature(v), h.Signature(u), 64)
+		sum += math.Abs(got - cosine(v, u))
+	}
+	if avg := sum / n; avg > 0.1 {
+		t.Errorf("average cosine error=%v, want <=0.1", avg)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	const dim = 16
+	idx := NewIndex[float64](dim, 8, 4, 5)
+	vecs := snm.Slice(200, func(int) []float64 {
+		return snm.Slice(dim, func(int) float64 { return rand.NormFloat64() })
+	})
+	for i, v := range vecs {
+		if id := idx.Add(v); id != i {
+			t.Fatalf("Add(...)=%v, want %v", id, i)
+		}
+	}
+	if idx.Len() != len(vecs) {
+		t.Fatalf("Len()=%v, want %v", idx.Len(), len(vecs))
+	}
+
+	for i, v := range vecs {
+		c := idx.Candidates(v)
+		if !slices.IsSorted(c) {
+			t.Fatalf("Candidates(%v) not sorted: %v", i, c)
+		}
+		if _, ok := slices.BinarySearch(c, i); !ok {
+			t.Fatalf("Candidates(%v)=%v, want to contain %v", i, c, i)
+		}
+		res := idx.Search(v, 0)
+		if len(res) == 0 || res[0].Hamming != 0 {
+			t.Fatalf("Search(%v,0)=%v, want a zero-distance result", i, res)
+		}
+		for _, r := range res {
+			if r.Hamming != 0 || idx.Signature(r.ID) != idx.Signature(i) {
+				t.Fatalf("Search(%v,0) returned %v", i, r)
+			}
+		}
+	}
+
+	// A slightly perturbed vector should find its origin.
+	found := 0
+	for i, v := range vecs {
+		u := snm.SliceToSlice(v, func(x float64) float64 {
+			return x + rand.NormFloat64()*0.05
+		})
+		res := idx.Search(u, 64)
+		if !slices.IsSortedFunc(res, func(a, b Result) int {
+			return a.Hamming - b.Hamming
+		}) {
+			t.Fatalf("Search(...) not sorted: %v", res)
+		}
+		if slices.ContainsFunc(res, func(r Result) bool { return r.ID == i }) {
+			found++
+		}
+	}
+	if found < len(vecs)*9/10 {
+		t.Errorf("found %v perturbed vectors, want at least %v",
+			found, len(vecs)*9/10)
+	}
+}
+
+func cosine(a, b []float64) float64 {
+	var ab, aa, bb float64
+	for i := range a {
+		ab += a[i] * b[i]
+		aa += a[i] * a[i]
+		bb += b[i] * b[i]
+	}
+	return ab / math.Sqrt(aa*bb)
+}
@@ -0,0 +1,174 @@
+// Package memo provides memoization of functions.
+//
+// Memoized functions are safe for concurrent use. Concurrent calls with the
+// same argument share a single call to the underlying function.
+package memo
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// An Option configures a memoized function.
+type Option func(*options)
+
+type options struct {
+	capacity int
+	ttl      time.Duration
+}
+
+// WithCapacity limits the number of cached results to n.
+// When the limit is reached, the least recently used result is evicted.
+func WithCapacity(n int) Option {
+	if n < 1 {
+		panic(fmt.Sprintf("bad capacity: %d", n))
+	}
+	return func(o *options) { o.capacity = n }
+}
+
+// WithTTL makes cached results expire after d.
+// Expired results are dropped as new ones are added.
+func WithTTL(d time.Duration) Option {
+	if d <= 0 {
+		panic(fmt.Sprintf("bad ttl: %v", d))
+	}
+	return func(o *options) { o.ttl = d }
+}
+
+// Memoize returns a function that calls f once per argument and
+// caches the results. By default the cache is unbounded and results never
+// expire.
+//
+// If f panics, the panic is propagated to all callers that waited for
+// that call, and the result is not cached.
+func Memoize[K comparable, V any](f func(K) V, opts ...Option) func(K) V {
+	c := newCache(func(k K) (V, error) { return f(k), nil }, opts)
+	return func(k K) V {
+		v, _ := c.get(k)
+		return v
+	}
+}
+
+// MemoizeErr returns a function that calls f once per argument and
+// caches the results. Errors are returned to all callers that waited for
+// the failed call, but are not cached.
+func MemoizeErr[K comparable, V any](f func(K) (V, error),
+	opts ...Option) func(K) (V, error) {
+	return newCache(f, opts).get
+}
+
+// Caches the results of a function.
+type cache[K comparable, V any] struct {
+	f   func(K) (V, error)
+	opt options
+	now func() time.Time
+
+	mu   sync.Mutex
+	m    map[K]*entry[K, V]
+	lru  *list.List // Keys of completed entries, most recent first.
+	ages *list.List // Keys of completed entries, oldest first.
+}
+
+// A single cached result.
+type entry[K comparable, V any] struct {
+	val     V
+	err     error
+	pval    any           // Recovered panic value, if f panicked.
+	done    chan struct{} // Closed when the result is ready.
+	ready   bool          // Guarded by the cache's mutex.
+	expires time.Time
+	elem    *list.Element // Element in the LRU list.
+	age     *list.Element // Element in the age list.
+}
+
+// Returns a cache for f.
+func newCache[K comparable, V any](f func(K) (V, error),
+	opts []Option) *cache[K, V] {
+	c := &cache[K, V]{f: f, now: time.Now, m: map[K]*entry[K, V]{},
+		lru: list.New(), ages: list.New()}
+	for _, o := range opts {
+		o(&c.opt)
+	}
+	return c
+}
+
+// Returns the result for k, calling f if needed.
+func (c *cache[K, V]) get(k K) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.m[k]; ok {
+		if !e.ready { // In flight.
+			c.mu.Unlock()
+			<-e.done
+			if e.pval != nil {
+				panic(e.pval)
+			}
+			return e.val, e.err
+		}
+		if c.opt.ttl == 0 || c.now().Before(e.expires) {
+			c.lru.MoveToFront(e.elem)
+			c.mu.Unlock()
+			return e.val, e.err
+		}
+		c.remove(k, e)
+	}
+	e := &entry[K, V]{done: make(chan struct{})}
+	c.m[k] = e
+	c.mu.Unlock()
+
+	completed := false
+	defer func() {
+		e.pval = recover()
+		c.mu.Lock()
+		if !completed || e.err != nil {
+			if c.m[k] == e {
+				delete(c.m, k)
+			}
+			if e.pval == nil && !completed { // Goexit.
+				e.err = fmt.Errorf("memoized function exited")
+			}
+		} else if c.m[k] == e {
+			c.add(k, e)
+		}
+		c.mu.Unlock()
+		close(e.done)
+		if e.pval != nil {
+			panic(e.pval)
+		}
+	}()
+	e.val, e.err = c.f(k)
+	completed = true
+	return e.val, e.err
+}
+
+// Marks an entry as completed, and evicts expired and excess entries.
+// Must be called with the mutex held.
+func (c *cache[K, V]) add(k K, e *entry[K, V]) {
+	now := c.now()
+	e.ready = true
+	e.expires = now.Add(c.opt.ttl)
+	e.elem = c.lru.PushFront(k)
+	e.age = c.ages.PushBack(k)
+	if c.opt.ttl > 0 {
+		for front := c.ages.Front(); front != nil; front = c.ages.Front() {
+			fk := front.Value.(K)
+			fe := c.m[fk]
+			if now.Before(fe.expires) {
+				break
+			}
+			c.remove(fk, fe)
+		}
+	}
+	if c.opt.capacity > 0 && c.lru.Len() > c.opt.capacity {
+		lk := c.lru.Back().Value.(K)
+		c.remove(lk, c.m[lk])
+	}
+}
+
+// Removes a completed entry. Must be called with the mutex held.
+func (c *cache[K, V]) remove(k K, e *entry[K, V]) {
+	c.lru.Remove(e.elem)
+	c.ages.Remove(e.age)
+	delete(c.m, k)
+}
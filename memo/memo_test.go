@@ -0,0 +1,170 @@
+package memo
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	f := Memoize(func(i int) int { calls++; return i * i })
+	for range 3 {
+		for i := range 5 {
+			if got := f(i); got != i*i {
+				t.Fatalf("f(%v)=%v, want %v", i, got, i*i)
+			}
+		}
+	}
+	if calls != 5 {
+		t.Fatalf("calls=%v, want 5", calls)
+	}
+}
+
+func TestMemoizeErr(t *testing.T) {
+	calls := 0
+	f := MemoizeErr(func(i int) (int, error) {
+		calls++
+		if i < 0 {
+			return 0, fmt.Errorf("negative")
+		}
+		return i, nil
+	})
+	for range 3 {
+		if _, err := f(-1); err == nil {
+			t.Fatalf("f(-1) succeeded, want error")
+		}
+		if got, err := f(1); err != nil || got != 1 {
+			t.Fatalf("f(1)=%v,%v, want 1,nil", got, err)
+		}
+	}
+	if calls != 4 { // 3 for errors, 1 for success.
+		t.Fatalf("calls=%v, want 4", calls)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	var calls []int
+	f := Memoize(func(i int) int { calls = append(calls, i); return i },
+		WithCapacity(2))
+	for _, i := range []int{1, 2, 1, 3, 1, 2} {
+		f(i)
+	}
+	// 2 is evicted when 3 is added, since 1 was used more recently.
+	want := []int{1, 2, 3, 2}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Fatalf("calls=%v, want %v", calls, want)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	calls := 0
+	c := newCache(func(i int) (int, error) { calls++; return i, nil },
+		[]Option{WithTTL(time.Minute)})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.get(1)
+	now = now.Add(30 * time.Second)
+	c.get(1)
+	if calls != 1 {
+		t.Fatalf("calls=%v, want 1", calls)
+	}
+	now = now.Add(time.Minute)
+	c.get(1)
+	if calls != 2 {
+		t.Fatalf("calls=%v, want 2", calls)
+	}
+}
+
+func TestSingleFlight(t *testing.T) {
+	var calls atomic.Int32
+	start := make(chan struct{})
+	f := Memoize(func(i int) int {
+		calls.Add(1)
+		<-start
+		return i + 1
+	})
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = f(5)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(start)
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("calls=%v, want 1", calls.Load())
+	}
+	for _, r := range results {
+		if r != 6 {
+			t.Fatalf("f(5)=%v, want 6", r)
+		}
+	}
+}
+
+func TestPanic(t *testing.T) {
+	calls := 0
+	f := Memoize(func(i int) int {
+		calls++
+		if calls == 1 {
+			panic("oops")
+		}
+		return i
+	})
+	func() {
+		defer func() { recover() }()
+		f(1)
+	}()
+	if got := f(1); got != 1 {
+		t.Fatalf("f(1)=%v, want 1", got)
+	}
+}
+
+func TestPanic_waiters(t *testing.T) {
+	start := make(chan struct{})
+	f := Memoize(func(i int) int {
+		<-start
+		panic("oops")
+	})
+	var wg sync.WaitGroup
+	var panics atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if recover() != nil {
+					panics.Add(1)
+				}
+			}()
+			f(1)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(start)
+	wg.Wait()
+	if panics.Load() != 5 {
+		t.Fatalf("panics=%v, want 5", panics.Load())
+	}
+}
+
+func TestTTL_sweep(t *testing.T) {
+	c := newCache(func(i int) (int, error) { return i, nil },
+		[]Option{WithTTL(time.Minute)})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	for i := range 10 {
+		c.get(i)
+	}
+	now = now.Add(2 * time.Minute)
+	c.get(100)
+	if len(c.m) != 1 {
+		t.Fatalf("len(m)=%v, want 1", len(c.m))
+	}
+}
@@ -0,0 +1,304 @@
+// Package metrics provides a lightweight registry of counters, gauges,
+// histograms and timers, for exposing internal stats of long-running jobs.
+//
+// All types are safe for concurrent use.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fluhus/gostuff/gnum"
+	"github.com/fluhus/gostuff/reservoir"
+)
+
+// Number of values kept by a histogram for estimating quantiles.
+const histogramSample = 1024
+
+// A Registry holds named metrics.
+// Each metric kind has its own namespace.
+type Registry struct {
+	mu         sync.Mutex
+	counters   map[string]*Counter
+	gauges     map[string]*Gauge
+	histograms map[string]*Histogram
+	timers     map[string]*Timer
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		counters:   map[string]*Counter{},
+		gauges:     map[string]*Gauge{},
+		histograms: map[string]*Histogram{},
+		timers:     map[string]*Timer{},
+	}
+}
+
+// Returns the metric with the given name, creating it if needed.
+func getOrNew[T any](r *Registry, m map[string]*T, name string,
+	f func() *T) *T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	x, ok := m[name]
+	if !ok {
+		x = f()
+		m[name] = x
+	}
+	return x
+}
+
+// Counter returns the counter with the given name, creating it if needed.
+func (r *Registry) Counter(name string) *Counter {
+	return getOrNew(r, r.counters, name, func() *Counter {
+		return &Counter{}
+	})
+}
+
+// Gauge returns the gauge with the given name, creating it if needed.
+func (r *Registry) Gauge(name string) *Gauge {
+	return getOrNew(r, r.gauges, name, func() *Gauge {
+		return &Gauge{}
+	})
+}
+
+// Histogram returns the histogram with the given name, creating it if
+// needed.
+func (r *Registry) Histogram(name string) *Histogram {
+	return getOrNew(r, r.histograms, name, newHistogram)
+}
+
+// Timer returns the timer with the given name, creating it if needed.
+func (r *Registry) Timer(name string) *Timer {
+	return getOrNew(r, r.timers, name, func() *Timer {
+		return &Timer{newHistogram()}
+	})
+}
+
+// A Counter is an integer that only goes up.
+type Counter struct {
+	n atomic.Int64
+}
+
+// Inc adds 1 to the counter.
+func (c *Counter) Inc() {
+	c.n.Add(1)
+}
+
+// Add adds n to the counter. Panics if n is negative.
+func (c *Counter) Add(n int64) {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	c.n.Add(n)
+}
+
+// Value returns the counter's current value.
+func (c *Counter) Value() int64 {
+	return c.n.Load()
+}
+
+// A Gauge is a float value that can be set arbitrarily.
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set sets the gauge's value.
+func (g *Gauge) Set(x float64) {
+	g.bits.Store(math.Float64bits(x))
+}
+
+// Add adds x to the gauge's value.
+func (g *Gauge) Add(x float64) {
+	for {
+		old := g.bits.Load()
+		nu := math.Float64bits(math.Float64frombits(old) + x)
+		if g.bits.CompareAndSwap(old, nu) {
+			return
+		}
+	}
+}
+
+// Value returns the gauge's current value.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+// A Histogram summarizes a distribution of values.
+// Quantiles are estimated from a uniform sample of the values.
+type Histogram struct {
+	mu       sync.Mutex
+	count    int64
+	sum      float64
+	min, max float64
+	sample   *reservoir.Sampler[float64]
+}
+
+func newHistogram() *Histogram {
+	return &Histogram{sample: reservoir.New[float64](histogramSample)}
+}
+
+// Observe adds a value to the histogram.
+func (h *Histogram) Observe(x float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 || x < h.min {
+		h.min = x
+	}
+	if h.count == 0 || x > h.max {
+		h.max = x
+	}
+	h.count++
+	h.sum += x
+	h.sample.Add(x)
+}
+
+// Snapshot returns a summary of the values observed so far.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	s := HistogramSnapshot{Count: h.count, Sum: h.sum, Min: h.min, Max: h.max}
+	sample := slices.Clone(h.sample.Elements)
+	h.mu.Unlock()
+
+	if s.Count == 0 {
+		return s
+	}
+	s.Mean = s.Sum / float64(s.Count)
+	slices.Sort(sample)
+	q := gnum.Quantiles(sample, 0.5, 0.9, 0.99)
+	s.P50, s.P90, s.P99 = q[0], q[1], q[2]
+	return s
+}
+
+// HistogramSnapshot is a summary of a histogram's values.
+type HistogramSnapshot struct {
+	Count         int64
+	Sum           float64
+	Min, Max      float64
+	Mean          float64
+	P50, P90, P99 float64 // Estimated quantiles
+}
+
+// A Timer is a histogram of durations, in seconds.
+type Timer struct {
+	h *Histogram
+}
+
+// Observe adds a duration to the timer.
+func (t *Timer) Observe(d time.Duration) {
+	t.h.Observe(d.Seconds())
+}
+
+// Since adds the time passed since start to the timer.
+func (t *Timer) Since(start time.Time) {
+	t.Observe(time.Since(start))
+}
+
+// Time runs f and adds its running time to the timer.
+func (t *Timer) Time(f func()) {
+	defer t.Since(time.Now())
+	f()
+}
+
+// Snapshot returns a summary of the durations observed so far, in seconds.
+func (t *Timer) Snapshot() HistogramSnapshot {
+	return t.h.Snapshot()
+}
+
+// A Snapshot holds the values of a registry's metrics at a point in time.
+type Snapshot struct {
+	Counters   map[string]int64
+	Gauges     map[string]float64
+	Histograms map[string]HistogramSnapshot
+	Timers     map[string]HistogramSnapshot
+}
+
+// Snapshot returns the current values of all metrics.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	counters := maps.Clone(r.counters)
+	gauges := maps.Clone(r.gauges)
+	histograms := maps.Clone(r.histograms)
+	timers := maps.Clone(r.timers)
+	r.mu.Unlock()
+
+	s := Snapshot{
+		Counters:   make(map[string]int64, len(counters)),
+		Gauges:     make(map[string]float64, len(gauges)),
+		Histograms: make(map[string]HistogramSnapshot, len(histograms)),
+		Timers:     make(map[string]HistogramSnapshot, len(timers)),
+	}
+	for k, v := range counters {
+		s.Counters[k] = v.Value()
+	}
+	for k, v := range gauges {
+		s.Gauges[k] = v.Value()
+	}
+	for k, v := range histograms {
+		s.Histograms[k] = v.Snapshot()
+	}
+	for k, v := range timers {
+		s.Timers[k] = v.Snapshot()
+	}
+	return s
+}
+
+// WriteTo writes the current values of all metrics to w as text,
+// one metric per line, sorted by kind and name.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	return r.Snapshot().WriteTo(w)
+}
+
+// WriteTo writes the snapshot to w as text, one metric per line,
+// sorted by kind and name.
+func (s Snapshot) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	for _, k := range sortedKeys(s.Counters) {
+		fmt.Fprintf(cw, "counter %s %d\n", k, s.Counters[k])
+	}
+	for _, k := range sortedKeys(s.Gauges) {
+		fmt.Fprintf(cw, "gauge %s %g\n", k, s.Gauges[k])
+	}
+	for _, k := range sortedKeys(s.Histograms) {
+		writeHistogram(cw, "histogram", k, s.Histograms[k])
+	}
+	for _, k := range sortedKeys(s.Timers) {
+		writeHistogram(cw, "timer", k, s.Timers[k])
+	}
+	return cw.n, cw.err
+}
+
+// Writes a single histogram line.
+func writeHistogram(w io.Writer, kind, name string, h HistogramSnapshot) {
+	fmt.Fprintf(w, "%s %s count=%d sum=%g min=%g max=%g mean=%g "+
+		"p50=%g p90=%g p99=%g\n", kind, name, h.Count, h.Sum, h.Min, h.Max,
+		h.Mean, h.P50, h.P90, h.P99)
+}
+
+// Returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// A writer that counts written bytes and keeps the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *countWriter) Write(b []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	w.err = err
+	return n, err
+}
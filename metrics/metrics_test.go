@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				r.Counter("a").Inc()
+			}
+			r.Counter("b").Add(2)
+		}()
+	}
+	wg.Wait()
+	if got := r.Counter("a").Value(); got != 1000 {
+		t.Errorf("Counter(a).Value()=%v, want 1000", got)
+	}
+	if got := r.Counter("b").Value(); got != 20 {
+		t.Errorf("Counter(b).Value()=%v, want 20", got)
+	}
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.Gauge("a")
+	g.Set(1.5)
+	g.Add(2)
+	if got := r.Gauge("a").Value(); got != 3.5 {
+		t.Errorf("Gauge(a).Value()=%v, want 3.5", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewRegistry().Histogram("a")
+	if got := h.Snapshot(); got != (HistogramSnapshot{}) {
+		t.Errorf("Snapshot()=%v, want empty", got)
+	}
+	for i := 1; i <= 100; i++ {
+		h.Observe(float64(i))
+	}
+	got := h.Snapshot()
+	want := HistogramSnapshot{Count: 100, Sum: 5050, Min: 1, Max: 100,
+		Mean: 50.5, P50: 51, P90: 90, P99: 99}
+	if got != want {
+		t.Errorf("Snapshot()=%+v, want %+v", got, want)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	tm := NewRegistry().Timer("a")
+	tm.Observe(2 * time.Second)
+	tm.Time(func() {})
+	got := tm.Snapshot()
+	if got.Count != 2 || got.Max != 2 {
+		t.Errorf("Snapshot()=%+v, want Count=2 Max=2", got)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("b").Add(3)
+	r.Counter("a").Inc()
+	r.Gauge("g").Set(0.5)
+	r.Histogram("h").Observe(2)
+	buf := bytes.NewBuffer(nil)
+	n, err := r.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	want := "counter a 1\ncounter b 3\ngauge g 0.5\n" +
+		"histogram h count=1 sum=2 min=2 max=2 mean=2 p50=2 p90=2 p99=2\n"
+	if buf.String() != want {
+		t.Errorf("WriteTo()=%q, want %q", buf.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo()=%v, want %v", n, len(want))
+	}
+}
@@ -0,0 +1,132 @@
+// Package mmarray provides read-only access to on-disk numeric arrays
+// through memory mapping.
+//
+// An array file holds fixed-size little-endian values with no header,
+// as written by [WriteFile]. Arrays can also be embedded in other files
+// at an aligned offset, and opened with [OpenOffset].
+package mmarray
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/fluhus/gostuff/internal/mmap"
+)
+
+// Number is a fixed-size numeric type that can be mapped.
+type Number interface {
+	int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64
+}
+
+// An Array is a read-only slice backed by a memory-mapped file.
+type Array[T Number] struct {
+	s    []T
+	data []byte
+}
+
+// Open maps the given array file.
+// The array must be closed to release the mapping.
+func Open[T Number](file string) (*Array[T], error) {
+	return OpenOffset[T](file, 0)
+}
+
+// OpenOffset maps the array that starts at the given byte offset of the
+// file and continues until its end. The offset must be a multiple of the
+// element size.
+// The array must be closed to release the mapping.
+func OpenOffset[T Number](file string, offset int64) (*Array[T], error) {
+	if !littleEndian() {
+		return nil, fmt.Errorf("mapping requires a little-endian platform")
+	}
+	size := int64(unsafe.Sizeof(T(0)))
+	if offset < 0 || offset%size != 0 {
+		return nil, fmt.Errorf("bad offset for %d-byte elements: %d",
+			size, offset)
+	}
+	data, err := mmap.Map(file)
+	if err != nil {
+		return nil, err
+	}
+	s, err := asSlice[T](data, offset)
+	if err != nil {
+		mmap.Unmap(data)
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &Array[T]{s, data}, nil
+}
+
+// Returns the values of data starting at offset.
+func asSlice[T Number](data []byte, offset int64) ([]T, error) {
+	size := int64(unsafe.Sizeof(T(0)))
+	if offset > int64(len(data)) {
+		return nil, fmt.Errorf("offset %d is beyond data length %d",
+			offset, len(data))
+	}
+	data = data[offset:]
+	if int64(len(data))%size != 0 {
+		return nil, fmt.Errorf("data length %d is not a multiple of %d",
+			len(data), size)
+	}
+	if len(data) == 0 {
+		return []T{}, nil
+	}
+	p := unsafe.Pointer(unsafe.SliceData(data))
+	if uintptr(p)%unsafe.Alignof(T(0)) != 0 {
+		return nil, fmt.Errorf("data is not aligned to %d bytes",
+			unsafe.Alignof(T(0)))
+	}
+	return unsafe.Slice((*T)(p), int64(len(data))/size), nil
+}
+
+// Slice returns the array's values.
+// The slice must not be modified, and must not be used after closing
+// the array.
+func (a *Array[T]) Slice() []T {
+	return a.s
+}
+
+// Len returns the number of values in the array.
+func (a *Array[T]) Len() int {
+	return len(a.s)
+}
+
+// At returns the i'th value.
+func (a *Array[T]) At(i int) T {
+	return a.s[i]
+}
+
+// Close releases the mapping.
+func (a *Array[T]) Close() error {
+	a.s = nil
+	data := a.data
+	a.data = nil
+	return mmap.Unmap(data)
+}
+
+// WriteFile writes s to the given file as a little-endian array that can
+// be opened with [Open].
+func WriteFile[T Number](file string, s []T) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := binary.Write(w, binary.LittleEndian, s); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Returns whether the platform is little-endian.
+func littleEndian() bool {
+	return binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+}
@@ -0,0 +1,84 @@
+package mmarray
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.bin")
+	want := []float32{1.5, -2, 3.25, 0, 1e10}
+	if err := WriteFile(file, want); err != nil {
+		t.Fatalf("WriteFile(%v) failed: %v", want, err)
+	}
+	a, err := Open[float32](file)
+	if err != nil {
+		t.Fatalf("Open(%q) failed: %v", file, err)
+	}
+	defer a.Close()
+	if got := a.Slice(); !slices.Equal(got, want) {
+		t.Errorf("Open(%q)=%v, want %v", file, got, want)
+	}
+	if a.Len() != len(want) || a.At(2) != want[2] {
+		t.Errorf("Len(),At(2)=%v,%v, want %v,%v",
+			a.Len(), a.At(2), len(want), want[2])
+	}
+}
+
+func TestOpenOffset(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.bin")
+	if err := WriteFile(file, []uint64{1, 2, 3, 1 << 60}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := OpenOffset[uint64](file, 16)
+	if err != nil {
+		t.Fatalf("OpenOffset(%q, 16) failed: %v", file, err)
+	}
+	defer a.Close()
+	if got, want := a.Slice(), []uint64{3, 1 << 60}; !slices.Equal(got, want) {
+		t.Errorf("OpenOffset(%q, 16)=%v, want %v", file, got, want)
+	}
+
+	for _, offset := range []int64{-8, 4, 40} {
+		if a, err := OpenOffset[uint64](file, offset); err == nil {
+			a.Close()
+			t.Errorf("OpenOffset(%q, %d) succeeded, want error", file, offset)
+		}
+	}
+}
+
+func TestOpen_badLength(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.bin")
+	if err := os.WriteFile(file, make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if a, err := Open[uint32](file); err == nil {
+		a.Close()
+		t.Errorf("Open(10 bytes as uint32) succeeded, want error")
+	}
+	a, err := Open[uint16](file)
+	if err != nil {
+		t.Fatalf("Open(10 bytes as uint16) failed: %v", err)
+	}
+	defer a.Close()
+	if a.Len() != 5 {
+		t.Errorf("Len()=%v, want 5", a.Len())
+	}
+}
+
+func TestOpen_empty(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "a.bin")
+	if err := WriteFile[int32](file, nil); err != nil {
+		t.Fatal(err)
+	}
+	a, err := Open[int32](file)
+	if err != nil {
+		t.Fatalf("Open(empty) failed: %v", err)
+	}
+	defer a.Close()
+	if a.Len() != 0 {
+		t.Errorf("Len()=%v, want 0", a.Len())
+	}
+}
@@ -0,0 +1,74 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// A Group runs functions concurrently and collects their results.
+// It is like errgroup, with typed results.
+//
+// A Group must not be reused after calling Wait.
+type Group[T any] struct {
+	cancel  context.CancelFunc
+	sem     chan struct{} // Nil if there is no limit.
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	results []T
+	err     error
+}
+
+// NewGroup returns a group that runs up to limit functions at once, and a
+// context derived from ctx that is canceled when a function returns an
+// error or when Wait returns. A limit of 0 means no limit.
+func NewGroup[T any](ctx context.Context, limit int,
+) (*Group[T], context.Context) {
+	if limit < 0 {
+		panic(fmt.Sprintf("bad limit: %d", limit))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group[T]{cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+// Go runs fn in a new goroutine. Blocks while the limit of running
+// functions is reached.
+func (g *Group[T]) Go(fn func() (T, error)) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.mu.Lock()
+	i := len(g.results)
+	var zero T
+	g.results = append(g.results, zero)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		t, err := fn()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.results[i] = t
+		if err != nil && g.err == nil {
+			g.err = err
+			g.cancel()
+		}
+	}()
+}
+
+// Wait waits for all functions to return. Returns their results in the
+// order they were passed to Go, and the first error returned by any of
+// them.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	g.cancel()
+	return g.results, g.err
+}
@@ -0,0 +1,54 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	g, _ := NewGroup[int](context.Background(), 2)
+	var running, maxRunning atomic.Int32
+	for i := range 10 {
+		g.Go(func() (int, error) {
+			r := running.Add(1)
+			if r > maxRunning.Load() {
+				maxRunning.Store(r)
+			}
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			running.Add(-1)
+			return i * 2, nil
+		})
+	}
+	got, err := g.Wait()
+	if err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	want := []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Wait()=%v, want %v", got, want)
+	}
+	if m := maxRunning.Load(); m > 2 {
+		t.Fatalf("max running=%v, want at most 2", m)
+	}
+}
+
+func TestGroupError(t *testing.T) {
+	g, ctx := NewGroup[int](context.Background(), 0)
+	wantErr := errors.New("oops")
+	g.Go(func() (int, error) { return 0, wantErr })
+	g.Go(func() (int, error) {
+		<-ctx.Done()
+		return 1, nil
+	})
+	got, err := g.Wait()
+	if err != wantErr {
+		t.Fatalf("Wait()=%v, want %v", err, wantErr)
+	}
+	if !slices.Equal(got, []int{0, 1}) {
+		t.Fatalf("Wait()=%v, want [0 1]", got)
+	}
+}
@@ -0,0 +1,91 @@
+// Package parallel provides helpers for running functions concurrently
+// with bounded parallelism.
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ForEachN calls fn with each of 0 to n-1, using the given number of
+// concurrent workers.
+//
+// All calls are made even if some return errors. If ctx is canceled,
+// no new calls are made. Returns the errors of all failed calls, ordered
+// by index and joined with [errors.Join], along with ctx's error if it was
+// canceled before all calls were made.
+func ForEachN(ctx context.Context, n, workers int,
+	fn func(ctx context.Context, i int) error) error {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	if workers < 1 {
+		panic(fmt.Sprintf("bad workers: %d", workers))
+	}
+
+	type indexedErr struct {
+		i   int
+		err error
+	}
+	var errs []indexedErr
+	var mu sync.Mutex
+	var next atomic.Int64
+	canceled := false
+	var wg sync.WaitGroup
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				if ctx.Err() != nil {
+					mu.Lock()
+					canceled = true
+					mu.Unlock()
+					return
+				}
+				if err := fn(ctx, i); err != nil {
+					mu.Lock()
+					errs = append(errs, indexedErr{i, err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(errs, func(a, b indexedErr) int { return a.i - b.i })
+	joined := make([]error, 0, len(errs)+1)
+	for _, e := range errs {
+		joined = append(joined, fmt.Errorf("item #%d: %w", e.i, e.err))
+	}
+	if canceled {
+		joined = append(joined, ctx.Err())
+	}
+	return errors.Join(joined...)
+}
+
+// Map returns the results of calling fn with each of the given items,
+// using the given number of concurrent workers. Results are in the order
+// of the items.
+//
+// Errors and cancellation are handled as in [ForEachN]. Results of failed
+// or skipped calls are zero values.
+func Map[T, U any](ctx context.Context, items []T, workers int,
+	fn func(ctx context.Context, item T) (U, error)) ([]U, error) {
+	result := make([]U, len(items))
+	err := ForEachN(ctx, len(items), workers,
+		func(ctx context.Context, i int) error {
+			u, err := fn(ctx, items[i])
+			result[i] = u
+			return err
+		})
+	return result, err
+}
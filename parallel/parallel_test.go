@@ -0,0 +1,95 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachN(t *testing.T) {
+	for _, workers := range []int{1, 3, 100} {
+		counts := make([]int32, 50)
+		var running, maxRunning atomic.Int32
+		err := ForEachN(context.Background(), len(counts), workers,
+			func(ctx context.Context, i int) error {
+				r := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if r <= m || maxRunning.CompareAndSwap(m, r) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&counts[i], 1)
+				running.Add(-1)
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("ForEachN(...) failed: %v", err)
+		}
+		for i, c := range counts {
+			if c != 1 {
+				t.Fatalf("count(%v)=%v, want 1", i, c)
+			}
+		}
+		if m := maxRunning.Load(); m > int32(workers) {
+			t.Fatalf("max running=%v, want at most %v", m, workers)
+		}
+	}
+}
+
+func TestForEachNErrors(t *testing.T) {
+	var calls atomic.Int32
+	err := ForEachN(context.Background(), 10, 3,
+		func(ctx context.Context, i int) error {
+			calls.Add(1)
+			if i%4 == 1 {
+				return fmt.Errorf("bad %d", i)
+			}
+			return nil
+		})
+	if calls.Load() != 10 {
+		t.Fatalf("calls=%v, want 10", calls.Load())
+	}
+	want := "item #1: bad 1\nitem #5: bad 5\nitem #9: bad 9"
+	if err == nil || err.Error() != want {
+		t.Fatalf("ForEachN(...)=%v, want %q", err, want)
+	}
+}
+
+func TestForEachNCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err := ForEachN(ctx, 1000, 2, func(ctx context.Context, i int) error {
+		if calls.Add(1) == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ForEachN(...)=%v, want %v", err, context.Canceled)
+	}
+	if c := calls.Load(); c > 12 {
+		t.Fatalf("calls=%v, want at most 12", c)
+	}
+}
+
+func TestMap(t *testing.T) {
+	items := []string{"a", "bb", "ccc", "dddd"}
+	got, err := Map(context.Background(), items, 2,
+		func(ctx context.Context, s string) (string, error) {
+			return strings.ToUpper(s), nil
+		})
+	if err != nil {
+		t.Fatalf("Map(...) failed: %v", err)
+	}
+	want := []string{"A", "BB", "CCC", "DDDD"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Map(...)=%v, want %v", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrClosed is returned when submitting tasks to a pool that was shut down.
+var ErrClosed = errors.New("pool is shut down")
+
+// A Pool runs submitted tasks on a fixed number of long-lived workers.
+// Panics in tasks are recovered, so a failing task does not crash the
+// process. Methods are safe for concurrent use.
+type Pool struct {
+	tasks   chan func()
+	quit    chan struct{} // Closed on shutdown, to release blocked submits.
+	workers sync.WaitGroup
+	senders sync.WaitGroup // Submits that may still send a task.
+
+	mu      sync.RWMutex // Guards closed and adding senders.
+	closed  bool
+	smu     sync.Mutex // Guards stats.
+	idle    *sync.Cond // Signaled when there are no pending tasks.
+	stats   Stats
+	pending int // Queued and running tasks.
+}
+
+// Stats holds a pool's task counts.
+type Stats struct {
+	Queued    int // Tasks waiting for a worker.
+	Running   int // Tasks currently running.
+	Completed int // Tasks that returned, including ones that panicked.
+	Panicked  int // Tasks that panicked.
+}
+
+// A PanicError holds a value recovered from a panicking task.
+type PanicError struct {
+	Value any    // The recovered value.
+	Stack []byte // Stack trace of the panicking goroutine.
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// NewPool returns a pool with the given number of workers. Up to
+// queueSize submitted tasks may wait for a worker before Submit blocks.
+func NewPool(workers, queueSize int) *Pool {
+	if workers < 1 {
+		panic(fmt.Sprintf("bad workers: %d", workers))
+	}
+	if queueSize < 0 {
+		panic(fmt.Sprintf("bad queueSize: %d", queueSize))
+	}
+	p := &Pool{tasks: make(chan func(), queueSize), quit: make(chan struct{})}
+	p.idle = sync.NewCond(&p.smu)
+	for range workers {
+		p.workers.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues task for execution. Blocks while the queue is full.
+// Returns ErrClosed if the pool was shut down.
+func (p *Pool) Submit(task func()) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	p.smu.Lock()
+	p.stats.Queued++
+	p.pending++
+	p.smu.Unlock()
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.quit:
+		p.smu.Lock()
+		p.stats.Queued--
+		p.pending--
+		if p.pending == 0 {
+			p.idle.Broadcast()
+		}
+		p.smu.Unlock()
+		return ErrClosed
+	}
+}
+
+// A Future holds the result of a task that may not have finished yet.
+type Future[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Wait waits for the task to finish and returns its result.
+// If the task panicked, returns a [*PanicError].
+func (f *Future[T]) Wait() (T, error) {
+	<-f.done
+	return f.val, f.err
+}
+
+// Done returns a channel that is closed when the task finishes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Submit queues fn for execution on p, and returns a future for its result.
+// Blocks while the queue is full. Returns ErrClosed if the pool was shut
+// down.
+func Submit[T any](p *Pool, fn func() (T, error)) (*Future[T], error) {
+	f := &Future[T]{done: make(chan struct{})}
+	err := p.Submit(func() {
+		defer close(f.done)
+		defer func() {
+			if r := recover(); r != nil {
+				f.err = &PanicError{r, debug.Stack()}
+				panic(r) // Let the pool count it.
+			}
+		}()
+		f.val, f.err = fn()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Drain waits until all submitted tasks have finished.
+// The pool remains open for new tasks.
+func (p *Pool) Drain() {
+	p.smu.Lock()
+	for p.pending > 0 {
+		p.idle.Wait()
+	}
+	p.smu.Unlock()
+}
+
+// Shutdown stops accepting new tasks and waits for the submitted tasks to
+// finish. Submits blocked on a full queue return ErrClosed.
+// If ctx is done first, returns its error while the remaining tasks
+// continue in the background.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.quit)
+		go func() {
+			// Close the task channel once no submit can send to it.
+			p.senders.Wait()
+			close(p.tasks)
+		}()
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the pool's current task counts.
+func (p *Pool) Stats() Stats {
+	p.smu.Lock()
+	defer p.smu.Unlock()
+	return p.stats
+}
+
+// Runs tasks until the task channel is closed.
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for task := range p.tasks {
+		p.smu.Lock()
+		p.stats.Queued--
+		p.stats.Running++
+		p.smu.Unlock()
+
+		panicked := p.run(task)
+
+		p.smu.Lock()
+		p.stats.Running--
+		p.stats.Completed++
+		if panicked {
+			p.stats.Panicked++
+		}
+		p.pending--
+		if p.pending == 0 {
+			p.idle.Broadcast()
+		}
+		p.smu.Unlock()
+	}
+}
+
+// Runs a task and returns whether it panicked.
+func (p *Pool) run(task func()) (panicked bool) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+		}
+	}()
+	task()
+	return false
+}
@@ -0,0 +1,122 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool(3, 10)
+	var sum atomic.Int64
+	for i := range 100 {
+		if err := p.Submit(func() { sum.Add(int64(i)) }); err != nil {
+			t.Fatalf("Submit(...) failed: %v", err)
+		}
+	}
+	p.Drain()
+	if got := sum.Load(); got != 4950 {
+		t.Fatalf("sum=%v, want 4950", got)
+	}
+	if s := p.Stats(); s != (Stats{Completed: 100}) {
+		t.Fatalf("Stats()=%+v, want 100 completed", s)
+	}
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown(...) failed: %v", err)
+	}
+	if err := p.Submit(func() {}); err != ErrClosed {
+		t.Fatalf("Submit(...) after shutdown=%v, want %v", err, ErrClosed)
+	}
+}
+
+func TestPoolFutures(t *testing.T) {
+	p := NewPool(2, 0)
+	defer p.Shutdown(context.Background())
+	var futures []*Future[int]
+	for i := range 10 {
+		f, err := Submit(p, func() (int, error) { return i * i, nil })
+		if err != nil {
+			t.Fatalf("Submit(...) failed: %v", err)
+		}
+		futures = append(futures, f)
+	}
+	for i, f := range futures {
+		if got, err := f.Wait(); err != nil || got != i*i {
+			t.Fatalf("Wait()=%v,%v, want %v,nil", got, err, i*i)
+		}
+	}
+
+	wantErr := errors.New("oops")
+	f, _ := Submit(p, func() (int, error) { return 0, wantErr })
+	if _, err := f.Wait(); err != wantErr {
+		t.Fatalf("Wait()=%v, want %v", err, wantErr)
+	}
+}
+
+func TestPoolPanic(t *testing.T) {
+	p := NewPool(1, 1)
+	defer p.Shutdown(context.Background())
+	f, _ := Submit(p, func() (int, error) { panic("boom") })
+	_, err := f.Wait()
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "boom" {
+		t.Fatalf("Wait()=%v, want PanicError(boom)", err)
+	}
+	p.Submit(func() { panic("boom2") })
+	p.Drain()
+	if s := p.Stats(); s.Panicked != 2 || s.Completed != 2 {
+		t.Fatalf("Stats()=%+v, want 2 panicked", s)
+	}
+
+	// The worker should survive.
+	f2, _ := Submit(p, func() (int, error) { return 1, nil })
+	if got, err := f2.Wait(); got != 1 || err != nil {
+		t.Fatalf("Wait()=%v,%v, want 1,nil", got, err)
+	}
+}
+
+func TestPoolShutdownTimeout(t *testing.T) {
+	p := NewPool(1, 1)
+	release := make(chan struct{})
+	p.Submit(func() { <-release })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown(...)=%v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown(...)=%v, want nil", err)
+	}
+}
+
+func TestPoolShutdownBlockedSubmit(t *testing.T) {
+	p := NewPool(1, 1)
+	release := make(chan struct{})
+	p.Submit(func() { <-release })
+	for p.Stats().Running == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	p.Submit(func() {}) // Fills the queue.
+	errc := make(chan error)
+	go func() { errc <- p.Submit(func() {}) }() // Blocks on a full queue.
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown(...)=%v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-errc; err != ErrClosed {
+		t.Fatalf("blocked Submit(...)=%v, want %v", err, ErrClosed)
+	}
+	close(release)
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown(...)=%v, want nil", err)
+	}
+	if got := p.Stats(); got.Completed != 2 || got.Queued != 0 {
+		t.Fatalf("Stats()=%+v, want 2 completed and none queued", got)
+	}
+}
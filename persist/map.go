@@ -0,0 +1,255 @@
+// Package persist provides immutable persistent data structures.
+//
+// Modifying a persistent structure returns a new version of it, while the
+// old version remains unchanged. Versions share most of their structure,
+// so modifications are cheap. Since versions never change, they can be
+// read concurrently without locks, and can be swapped atomically (for
+// example with [sync/atomic.Pointer]) to publish new state.
+package persist
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	bitsPerLevel = 5
+	branching    = 1 << bitsPerLevel
+	levelMask    = branching - 1
+)
+
+// Hash seed shared by all maps.
+var seed = maphash.MakeSeed()
+
+// A Map is a persistent hash map, implemented as a hash array mapped trie
+// (HAMT). The zero value and nil are empty maps.
+type Map[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	n    int
+}
+
+// A node in a map's trie.
+//
+// A regular node holds an entry for each set bit in its bitmap.
+// A collision node holds only leaves, with keys whose hashes are equal.
+type hamtNode[K comparable, V any] struct {
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+// A node entry, which is either a child node or a key-value leaf.
+type hamtEntry[K comparable, V any] struct {
+	child *hamtNode[K, V] // If not nil, this is a child entry.
+	hash  uint64
+	key   K
+	val   V
+}
+
+// NewMap returns an empty map.
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{}
+}
+
+// Len returns the number of elements in the map.
+func (m *Map[K, V]) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.n
+}
+
+// Get returns the value of k and true, or the zero value and false if
+// k is not in the map.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	var zero V
+	if m == nil || m.root == nil {
+		return zero, false
+	}
+	h := maphash.Comparable(seed, k)
+	n := m.root
+	for shift := 0; ; shift += bitsPerLevel {
+		if shift >= 64 { // Collision node.
+			for _, e := range n.entries {
+				if e.key == k {
+					return e.val, true
+				}
+			}
+			return zero, false
+		}
+		bit := uint32(1) << (h >> shift & levelMask)
+		if n.bitmap&bit == 0 {
+			return zero, false
+		}
+		e := &n.entries[n.pos(bit)]
+		if e.child == nil {
+			if e.key == k {
+				return e.val, true
+			}
+			return zero, false
+		}
+		n = e.child
+	}
+}
+
+// Set returns a version of the map where the value of k is v.
+func (m *Map[K, V]) Set(k K, v V) *Map[K, V] {
+	leaf := hamtEntry[K, V]{hash: maphash.Comparable(seed, k), key: k, val: v}
+	if m == nil || m.root == nil {
+		return &Map[K, V]{root: newHAMTNode(0, leaf), n: 1}
+	}
+	root, added := m.root.set(0, leaf)
+	n := m.n
+	if added {
+		n++
+	}
+	return &Map[K, V]{root: root, n: n}
+}
+
+// Delete returns a version of the map without k.
+// If k is not in the map, returns m.
+func (m *Map[K, V]) Delete(k K) *Map[K, V] {
+	if m == nil || m.root == nil {
+		return m
+	}
+	root, removed := m.root.delete(0, maphash.Comparable(seed, k), k)
+	if !removed {
+		return m
+	}
+	return &Map[K, V]{root: root, n: m.n - 1}
+}
+
+// All returns an iterator over the map's elements, in no particular order.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m != nil && m.root != nil {
+			m.root.all(yield)
+		}
+	}
+}
+
+// Returns the position of the entry of the given bit.
+func (n *hamtNode[K, V]) pos(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Returns a node with a single leaf, at the given level.
+func newHAMTNode[K comparable, V any](shift int, leaf hamtEntry[K, V],
+) *hamtNode[K, V] {
+	if shift >= 64 {
+		return &hamtNode[K, V]{entries: []hamtEntry[K, V]{leaf}}
+	}
+	return &hamtNode[K, V]{
+		bitmap:  1 << (leaf.hash >> shift & levelMask),
+		entries: []hamtEntry[K, V]{leaf},
+	}
+}
+
+// Returns a copy of n where leaf is set, and whether a new key was added.
+func (n *hamtNode[K, V]) set(shift int, leaf hamtEntry[K, V],
+) (*hamtNode[K, V], bool) {
+	if shift >= 64 { // Collision node.
+		for i, e := range n.entries {
+			if e.key == leaf.key {
+				return n.withEntry(i, leaf), false
+			}
+		}
+		return &hamtNode[K, V]{entries: append(slices.Clip(n.entries), leaf)},
+			true
+	}
+
+	bit := uint32(1) << (leaf.hash >> shift & levelMask)
+	i := n.pos(bit)
+	if n.bitmap&bit == 0 {
+		return &hamtNode[K, V]{
+			bitmap:  n.bitmap | bit,
+			entries: slices.Insert(slices.Clip(n.entries), i, leaf),
+		}, true
+	}
+
+	e := n.entries[i]
+	if e.child != nil {
+		child, added := e.child.set(shift+bitsPerLevel, leaf)
+		return n.withEntry(i, hamtEntry[K, V]{child: child}), added
+	}
+	if e.key == leaf.key {
+		return n.withEntry(i, leaf), false
+	}
+	child, _ := newHAMTNode(shift+bitsPerLevel, e).set(shift+bitsPerLevel, leaf)
+	return n.withEntry(i, hamtEntry[K, V]{child: child}), true
+}
+
+// Returns a copy of n without k, and whether k was removed.
+// Returns nil if the resulting node is empty.
+func (n *hamtNode[K, V]) delete(shift int, h uint64, k K,
+) (*hamtNode[K, V], bool) {
+	if shift >= 64 { // Collision node.
+		i := slices.IndexFunc(n.entries, func(e hamtEntry[K, V]) bool {
+			return e.key == k
+		})
+		if i == -1 {
+			return n, false
+		}
+		return n.without(i, 0), true
+	}
+
+	bit := uint32(1) << (h >> shift & levelMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := n.pos(bit)
+	e := n.entries[i]
+	if e.child == nil {
+		if e.key != k {
+			return n, false
+		}
+		return n.without(i, bit), true
+	}
+
+	child, removed := e.child.delete(shift+bitsPerLevel, h, k)
+	if !removed {
+		return n, false
+	}
+	if child == nil {
+		return n.without(i, bit), true
+	}
+	if len(child.entries) == 1 && child.entries[0].child == nil {
+		// Pull up a single leaf.
+		return n.withEntry(i, child.entries[0]), true
+	}
+	return n.withEntry(i, hamtEntry[K, V]{child: child}), true
+}
+
+// Returns a copy of n where entry i is replaced with e.
+func (n *hamtNode[K, V]) withEntry(i int, e hamtEntry[K, V]) *hamtNode[K, V] {
+	entries := slices.Clone(n.entries)
+	entries[i] = e
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}
+}
+
+// Returns a copy of n without entry i, whose bitmap bit is bit.
+// Returns nil if the resulting node is empty.
+func (n *hamtNode[K, V]) without(i int, bit uint32) *hamtNode[K, V] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	return &hamtNode[K, V]{
+		bitmap:  n.bitmap &^ bit,
+		entries: slices.Delete(slices.Clone(n.entries), i, i+1),
+	}
+}
+
+// Yields the elements under n. Returns false if iteration was stopped.
+func (n *hamtNode[K, V]) all(yield func(K, V) bool) bool {
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.all(yield) {
+				return false
+			}
+		} else if !yield(e.key, e.val) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package persist
+
+import (
+	"maps"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	var m *Map[int, int]
+	want := map[int]int{}
+	var versions []*Map[int, int]
+	var wants []map[int]int
+	for i := range 3000 {
+		k := rand.IntN(1000)
+		if rand.IntN(3) == 0 {
+			m = m.Delete(k)
+			delete(want, k)
+		} else {
+			m = m.Set(k, i)
+			want[k] = i
+		}
+		if m.Len() != len(want) {
+			t.Fatalf("Len()=%v, want %v", m.Len(), len(want))
+		}
+		if i%300 == 0 {
+			versions = append(versions, m)
+			wants = append(wants, maps.Clone(want))
+		}
+	}
+	for k := range 1000 {
+		v, ok := m.Get(k)
+		wv, wok := want[k]
+		if v != wv || ok != wok {
+			t.Fatalf("Get(%v)=%v,%v, want %v,%v", k, v, ok, wv, wok)
+		}
+	}
+	if got := maps.Collect(m.All()); !maps.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+
+	// Old versions should not change.
+	for i := range versions {
+		if got := maps.Collect(versions[i].All()); !maps.Equal(got, wants[i]) {
+			t.Fatalf("version #%v All()=%v, want %v", i, got, wants[i])
+		}
+	}
+}
+
+func TestMapDeleteAll(t *testing.T) {
+	m := NewMap[string, int]()
+	for _, s := range []string{"a", "b", "c"} {
+		m = m.Set(s, len(s))
+	}
+	for _, s := range []string{"a", "b", "c", "d"} {
+		m = m.Delete(s)
+	}
+	if m.Len() != 0 || m.root != nil {
+		t.Fatalf("Len()=%v root=%v, want 0,nil", m.Len(), m.root)
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Fatalf("Get(%q) found deleted key", "a")
+	}
+}
+
+func TestMapCollisions(t *testing.T) {
+	leaf := func(k int) hamtEntry[int, int] {
+		return hamtEntry[int, int]{hash: 12345, key: k, val: k * 10}
+	}
+	n := newHAMTNode(0, leaf(1))
+	n, _ = n.set(0, leaf(2))
+	n, added := n.set(0, leaf(3))
+	if !added {
+		t.Fatalf("set(3) added=false, want true")
+	}
+	if _, added := n.set(0, leaf(3)); added {
+		t.Fatalf("set(3) again added=true, want false")
+	}
+	if got := maps.Collect((&Map[int, int]{root: n}).All()); !maps.Equal(got,
+		map[int]int{1: 10, 2: 20, 3: 30}) {
+		t.Fatalf("all()=%v, want 3 elements", got)
+	}
+	n, _ = n.delete(0, 12345, 2)
+	n, _ = n.delete(0, 12345, 1)
+	if got := maps.Collect((&Map[int, int]{root: n}).All()); !maps.Equal(got, map[int]int{3: 30}) {
+		t.Fatalf("all()=%v, want {3:30}", got)
+	}
+	if len(n.entries) != 1 || n.entries[0].child != nil {
+		t.Fatalf("single leaf was not pulled up: %v", n.entries)
+	}
+	if n, _ = n.delete(0, 12345, 3); n != nil {
+		t.Fatalf("delete(3)=%v, want nil", n)
+	}
+}
@@ -0,0 +1,178 @@
+package persist
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// A Vector is a persistent list, implemented as a wide trie.
+// Access and modification take O(log32(n)) steps.
+// The zero value and nil are empty vectors.
+type Vector[T any] struct {
+	root  *vecNode[T]
+	shift int // Bit shift of the root level.
+	n     int
+}
+
+// A node in a vector's trie. Leaves hold values, other nodes hold children.
+type vecNode[T any] struct {
+	children []*vecNode[T]
+	vals     []T
+}
+
+// NewVector returns a vector with the given elements.
+func NewVector[T any](a ...T) *Vector[T] {
+	v := &Vector[T]{}
+	for _, x := range a {
+		v = v.Append(x)
+	}
+	return v
+}
+
+// Len returns the number of elements in the vector.
+func (v *Vector[T]) Len() int {
+	if v == nil {
+		return 0
+	}
+	return v.n
+}
+
+// At returns the i'th element.
+func (v *Vector[T]) At(i int) T {
+	v.checkIndex(i)
+	n := v.root
+	for shift := v.shift; shift > 0; shift -= bitsPerLevel {
+		n = n.children[i>>shift&levelMask]
+	}
+	return n.vals[i&levelMask]
+}
+
+// Set returns a version of the vector where the i'th element is x.
+func (v *Vector[T]) Set(i int, x T) *Vector[T] {
+	v.checkIndex(i)
+	return &Vector[T]{root: v.root.set(v.shift, i, x), shift: v.shift, n: v.n}
+}
+
+// Append returns a version of the vector with x added at the end.
+func (v *Vector[T]) Append(x T) *Vector[T] {
+	if v.Len() == 0 {
+		return &Vector[T]{root: newVecPath(0, x), n: 1}
+	}
+	if v.n == 1<<(v.shift+bitsPerLevel) { // Root is full.
+		root := &vecNode[T]{
+			children: []*vecNode[T]{v.root, newVecPath(v.shift, x)},
+		}
+		return &Vector[T]{root: root, shift: v.shift + bitsPerLevel, n: v.n + 1}
+	}
+	return &Vector[T]{root: v.root.append(v.shift, v.n, x), shift: v.shift,
+		n: v.n + 1}
+}
+
+// Pop returns a version of the vector without its last element.
+// Panics if the vector is empty.
+func (v *Vector[T]) Pop() *Vector[T] {
+	if v.Len() == 0 {
+		panic("pop from an empty vector")
+	}
+	if v.n == 1 {
+		return &Vector[T]{}
+	}
+	root, shift := v.root.pop(v.shift, v.n-1), v.shift
+	if shift > 0 && len(root.children) == 1 {
+		root, shift = root.children[0], shift-bitsPerLevel
+	}
+	return &Vector[T]{root: root, shift: shift, n: v.n - 1}
+}
+
+// All returns an iterator over the vector's indexes and elements.
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if v.Len() > 0 {
+			i := 0
+			v.root.all(&i, yield)
+		}
+	}
+}
+
+// Panics if i is out of range.
+func (v *Vector[T]) checkIndex(i int) {
+	if i < 0 || i >= v.Len() {
+		panic(fmt.Sprintf("index out of range: %d with length %d", i, v.Len()))
+	}
+}
+
+// Returns a path of nodes from the given level down to a leaf holding x.
+func newVecPath[T any](shift int, x T) *vecNode[T] {
+	if shift == 0 {
+		return &vecNode[T]{vals: []T{x}}
+	}
+	return &vecNode[T]{children: []*vecNode[T]{newVecPath(shift-bitsPerLevel, x)}}
+}
+
+// Returns a copy of n where the i'th element is x.
+func (n *vecNode[T]) set(shift, i int, x T) *vecNode[T] {
+	if shift == 0 {
+		vals := slices.Clone(n.vals)
+		vals[i&levelMask] = x
+		return &vecNode[T]{vals: vals}
+	}
+	children := slices.Clone(n.children)
+	j := i >> shift & levelMask
+	children[j] = children[j].set(shift-bitsPerLevel, i, x)
+	return &vecNode[T]{children: children}
+}
+
+// Returns a copy of n with x added as the i'th element.
+func (n *vecNode[T]) append(shift, i int, x T) *vecNode[T] {
+	if shift == 0 {
+		return &vecNode[T]{vals: append(slices.Clip(n.vals), x)}
+	}
+	j := i >> shift & levelMask
+	if j == len(n.children) {
+		return &vecNode[T]{children: append(slices.Clip(n.children),
+			newVecPath(shift-bitsPerLevel, x))}
+	}
+	children := slices.Clone(n.children)
+	children[j] = children[j].append(shift-bitsPerLevel, i, x)
+	return &vecNode[T]{children: children}
+}
+
+// Returns a copy of n without its last element, which is the i'th element.
+// Returns nil if the resulting node is empty.
+func (n *vecNode[T]) pop(shift, i int) *vecNode[T] {
+	if shift == 0 {
+		if len(n.vals) == 1 {
+			return nil
+		}
+		return &vecNode[T]{vals: n.vals[: len(n.vals)-1 : len(n.vals)-1]}
+	}
+	j := i >> shift & levelMask
+	child := n.children[j].pop(shift-bitsPerLevel, i)
+	if child == nil {
+		if j == 0 {
+			return nil
+		}
+		return &vecNode[T]{children: n.children[:j:j]}
+	}
+	children := slices.Clone(n.children)
+	children[j] = child
+	return &vecNode[T]{children: children}
+}
+
+// Yields the elements under n, counting indexes with i.
+// Returns false if iteration was stopped.
+func (n *vecNode[T]) all(i *int, yield func(int, T) bool) bool {
+	for _, c := range n.children {
+		if !c.all(i, yield) {
+			return false
+		}
+	}
+	for _, x := range n.vals {
+		if !yield(*i, x) {
+			return false
+		}
+		*i++
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package persist
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/fluhus/gostuff/snm"
+)
+
+func TestVector(t *testing.T) {
+	var v *Vector[int]
+	var want []int
+	var versions []*Vector[int]
+	var wants [][]int
+	for i := range 5000 {
+		switch r := rand.IntN(10); {
+		case r < 6:
+			v = v.Append(i)
+			want = append(want, i)
+		case r < 8 && len(want) > 0:
+			j := rand.IntN(len(want))
+			v = v.Set(j, -i)
+			want[j] = -i
+		case len(want) > 0:
+			v = v.Pop()
+			want = want[:len(want)-1]
+		}
+		if v.Len() != len(want) {
+			t.Fatalf("Len()=%v, want %v", v.Len(), len(want))
+		}
+		if i%500 == 0 {
+			versions = append(versions, v)
+			wants = append(wants, slices.Clone(want))
+		}
+	}
+	for i, x := range want {
+		if got := v.At(i); got != x {
+			t.Fatalf("At(%v)=%v, want %v", i, got, x)
+		}
+	}
+	for i := range versions {
+		var got []int
+		for _, x := range versions[i].All() {
+			got = append(got, x)
+		}
+		if !slices.Equal(got, wants[i]) {
+			t.Fatalf("version #%v All()=%v, want %v", i, got, wants[i])
+		}
+	}
+}
+
+func TestVectorPopAll(t *testing.T) {
+	v := NewVector(snm.Slice(1100, func(i int) int { return i })...)
+	if v.shift != 10 {
+		t.Fatalf("shift=%v, want 10", v.shift)
+	}
+	for i := 1099; i >= 0; i-- {
+		if got := v.At(i); got != i {
+			t.Fatalf("At(%v)=%v, want %v", i, got, i)
+		}
+		v = v.Pop()
+	}
+	if v.Len() != 0 || v.root != nil || v.shift != 0 {
+		t.Fatalf("Pop() all: Len()=%v shift=%v", v.Len(), v.shift)
+	}
+}
+
+func TestVectorIndexPanics(t *testing.T) {
+	v := NewVector(1, 2, 3)
+	for _, i := range []int{-1, 3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%v) did not panic", i)
+				}
+			}()
+			v.At(i)
+		}()
+	}
+}
@@ -0,0 +1,110 @@
+package ppln
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// Source returns a channel that receives the values of input, and is
+// closed when input is exhausted or ctx is done.
+func Source[T any](ctx context.Context, input iter.Seq[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for t := range input {
+			select {
+			case out <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Stage starts ngoroutines goroutines that apply transform to the values
+// received from in, and returns a channel that receives the results.
+// The order of results is arbitrary. The returned channel is closed when
+// in is closed and all results were sent, or when ctx is done.
+func Stage[T1 any, T2 any](ctx context.Context, in <-chan T1,
+	ngoroutines int, transform func(a T1) T2) <-chan T2 {
+	if ngoroutines < 1 {
+		panic(fmt.Sprintf("bad number of goroutines: %d", ngoroutines))
+	}
+	out := make(chan T2)
+	wg := &sync.WaitGroup{}
+	for range ngoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var t1 T1
+				var ok bool
+				select {
+				case t1, ok = <-in:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+				select {
+				case out <- transform(t1):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// FanIn returns a channel that receives the values of all the given
+// channels. The returned channel is closed when all input channels are
+// closed, or when ctx is done.
+func FanIn[T any](ctx context.Context, in ...<-chan T) <-chan T {
+	out := make(chan T)
+	wg := &sync.WaitGroup{}
+	for _, c := range in {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range c {
+				select {
+				case out <- t:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Collect returns the values received from in until it is closed.
+// If ctx is done first, returns the values received so far and ctx's
+// error.
+func Collect[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	var result []T
+	for {
+		select {
+		case t, ok := <-in:
+			if !ok {
+				return result, nil
+			}
+			result = append(result, t)
+		case <-ctx.Done():
+			return result, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ppln
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestChannelPipeline(t *testing.T) {
+	ctx := context.Background()
+	src := Source(ctx, slices.Values([]int{1, 2, 3, 4, 5}))
+	sq := Stage(ctx, src, 3, func(i int) int { return i * i })
+	got, err := Collect(ctx, sq)
+	if err != nil {
+		t.Fatalf("Collect(...) failed: %v", err)
+	}
+	slices.Sort(got)
+	if want := []int{1, 4, 9, 16, 25}; !slices.Equal(got, want) {
+		t.Fatalf("Collect(...)=%v, want %v", got, want)
+	}
+}
+
+func TestFanIn(t *testing.T) {
+	ctx := context.Background()
+	a := Source(ctx, slices.Values([]int{1, 2, 3}))
+	b := Source(ctx, slices.Values([]int{10, 20}))
+	got, err := Collect(ctx, FanIn(ctx, a, b))
+	if err != nil {
+		t.Fatalf("Collect(...) failed: %v", err)
+	}
+	slices.Sort(got)
+	if want := []int{1, 2, 3, 10, 20}; !slices.Equal(got, want) {
+		t.Fatalf("FanIn(...)=%v, want %v", got, want)
+	}
+}
+
+func TestChannelPipelineCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	infinite := func(yield func(int) bool) {
+		for i := 0; yield(i); i++ {
+		}
+	}
+	out := Stage(ctx, Source(ctx, infinite), 2, func(i int) int { return i })
+	for i := range out {
+		if i >= 100 {
+			cancel()
+			break
+		}
+	}
+	// Stage should close its output after cancellation.
+	for range out {
+	}
+
+	never := make(chan int)
+	if _, err := Collect(ctx, never); err != context.Canceled {
+		t.Fatalf("Collect(...)=%v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,142 @@
+package ptimer
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"time"
+)
+
+// A Progress reports the progress of a process with an optionally known total.
+// Unlike [Timer], it prints at fixed time intervals, and shows the rate and
+// the estimated time left.
+//
+// Output format:
+//
+//	00:00:01.000000 150/1000 (15.0%) 150.0/s ETA 00:00:05.666666
+//
+// When the total is unknown:
+//
+//	00:00:01.000000 150 150.0/s
+type Progress struct {
+	N        int           // Current count
+	Total    int           // Expected final count, or 0 if unknown
+	W        io.Writer     // Output, defaults to stderr
+	Interval time.Duration // Minimal time between prints, defaults to 1 second
+
+	t    time.Time        // Start time
+	last time.Time        // Last print time
+	now  func() time.Time // For testing
+}
+
+// NewProgress returns a new progress reporter with the given expected total.
+// A total of 0 means unknown.
+func NewProgress(total int) *Progress {
+	if total < 0 {
+		panic(fmt.Sprintf("bad total: %d", total))
+	}
+	t := time.Now()
+	return &Progress{Total: total, W: os.Stderr, Interval: time.Second,
+		t: t, last: t, now: time.Now}
+}
+
+// Inc increments the count by 1, and prints progress if the interval has
+// passed since the last print.
+func (p *Progress) Inc() {
+	p.Add(1)
+}
+
+// Add increments the count by n, and prints progress if the interval has
+// passed since the last print.
+func (p *Progress) Add(n int) {
+	p.N += n
+	if now := p.now(); now.Sub(p.last) >= p.Interval {
+		p.last = now
+		p.print(now)
+	}
+}
+
+// Done prints the final progress and a new line.
+func (p *Progress) Done() {
+	p.print(p.now())
+	fmt.Fprintln(p.W)
+}
+
+// Elapsed returns the time since p was created.
+func (p *Progress) Elapsed() time.Duration {
+	return p.now().Sub(p.t)
+}
+
+// Rate returns the average count per second.
+func (p *Progress) Rate() float64 {
+	return rate(p.N, p.Elapsed())
+}
+
+// ETA returns the estimated time left until the count reaches the total.
+// Returns false if the total is unknown or the count is 0.
+func (p *Progress) ETA() (time.Duration, bool) {
+	return eta(p.N, p.Total, p.Elapsed())
+}
+
+// Prints the progress at the given time.
+func (p *Progress) print(now time.Time) {
+	since := now.Sub(p.t)
+	r := rate(p.N, since)
+	if p.Total == 0 {
+		fmt.Fprintf(p.W, "\r%s %d %.1f/s", fmtDuration(since), p.N, r)
+		return
+	}
+	fmt.Fprintf(p.W, "\r%s %d/%d (%.1f%%) %.1f/s", fmtDuration(since),
+		p.N, p.Total, float64(p.N)*100/float64(p.Total), r)
+	if e, ok := eta(p.N, p.Total, since); ok {
+		fmt.Fprintf(p.W, " ETA %s", fmtDuration(e))
+	}
+}
+
+// Returns the count per second.
+func rate(n int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(n) / d.Seconds()
+}
+
+// Returns the estimated time left for reaching total.
+func eta(n, total int, d time.Duration) (time.Duration, bool) {
+	if total == 0 || n == 0 {
+		return 0, false
+	}
+	if n >= total {
+		return 0, true
+	}
+	return time.Duration(float64(d) * float64(total-n) / float64(n)), true
+}
+
+// Seq returns an iterator over the elements of s that increments p on each
+// element, and calls Done when s is exhausted.
+func Seq[T any](p *Progress, s iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := range s {
+			if !yield(x) {
+				return
+			}
+			p.Inc()
+		}
+		p.Done()
+	}
+}
+
+// Seq2 returns an iterator over the elements of s that increments p on each
+// element, and calls Done when s is exhausted.
+func Seq2[K, V any](p *Progress, s iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+			p.Inc()
+		}
+		p.Done()
+	}
+}
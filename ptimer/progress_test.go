@@ -0,0 +1,74 @@
+package ptimer
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Returns a progress whose clock advances by step on each reading.
+func fakeProgress(total int, step time.Duration) (*Progress, *bytes.Buffer) {
+	buf := bytes.NewBuffer(nil)
+	p := NewProgress(total)
+	p.W = buf
+	t := p.t
+	p.now = func() time.Time {
+		t = t.Add(step)
+		return t
+	}
+	return p, buf
+}
+
+func TestProgress(t *testing.T) {
+	p, buf := fakeProgress(4, 500*time.Millisecond)
+	for range 4 {
+		p.Inc()
+	}
+	p.Done()
+	want := "\r00:00:01.000000 2/4 (50.0%) 2.0/s ETA 00:00:01.000000" +
+		"\r00:00:02.000000 4/4 (100.0%) 2.0/s ETA 00:00:00.000000" +
+		"\r00:00:02.500000 4/4 (100.0%) 1.6/s ETA 00:00:00.000000\n"
+	if buf.String() != want {
+		t.Fatalf("Inc()+Done()=%q, want %q", buf.String(), want)
+	}
+}
+
+func TestProgress_unknownTotal(t *testing.T) {
+	p, buf := fakeProgress(0, time.Second)
+	p.Add(3)
+	p.Done()
+	want := "\r00:00:01.000000 3 3.0/s\r00:00:02.000000 3 1.5/s\n"
+	if buf.String() != want {
+		t.Fatalf("Add()+Done()=%q, want %q", buf.String(), want)
+	}
+	if _, ok := p.ETA(); ok {
+		t.Errorf("ETA()=_,true, want false")
+	}
+}
+
+func TestProgress_eta(t *testing.T) {
+	p, _ := fakeProgress(100, 0)
+	p.now = func() time.Time { return p.t.Add(10 * time.Second) }
+	p.N = 25
+	if got, ok := p.ETA(); !ok || got != 30*time.Second {
+		t.Errorf("ETA()=%v,%v, want %v,true", got, ok, 30*time.Second)
+	}
+	if got := p.Rate(); got != 2.5 {
+		t.Errorf("Rate()=%v, want 2.5", got)
+	}
+}
+
+func TestSeq(t *testing.T) {
+	p, buf := fakeProgress(3, time.Second)
+	got := slices.Collect(Seq(p, slices.Values([]int{1, 2, 3})))
+	if want := []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("Seq(...)=%v, want %v", got, want)
+	}
+	if p.N != 3 {
+		t.Errorf("N=%d, want 3", p.N)
+	}
+	if s := buf.String(); s == "" || s[len(s)-1] != '\n' {
+		t.Errorf("Seq(...) output=%q, want ending with new line", s)
+	}
+}
@@ -21,6 +21,9 @@
 // When calling Done without calling Inc:
 //
 //	00:00:00.000000 message
+//
+// For processes with a known total, or for printing at fixed intervals,
+// see [Progress].
 package ptimer
 
 import (
@@ -0,0 +1,171 @@
+// Package ratelimit provides token-bucket rate limiters.
+//
+// A bucket holds up to burst tokens, and is refilled at a fixed rate.
+// Each event takes one token, and events are allowed only while tokens
+// are available.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// A Limiter limits the rate of events. Methods are safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens per second.
+	burst  float64
+	tokens float64
+	last   time.Time // Last refill.
+	now    func() time.Time
+}
+
+// New returns a limiter that allows rate events per second on average,
+// with bursts of up to burst events. The limiter starts full.
+func New(rate float64, burst int) *Limiter {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		panic(fmt.Sprintf("bad rate: %v", rate))
+	}
+	if burst < 1 {
+		panic(fmt.Sprintf("bad burst: %d", burst))
+	}
+	return &Limiter{rate: rate, burst: float64(burst),
+		tokens: float64(burst), now: time.Now}
+}
+
+// Allow reports whether an event may happen now, and if so takes a token.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until an event may happen, and takes a token.
+// Returns ctx's error if it is done before then, in which case no token is
+// taken.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // Return the reserved token.
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Tokens returns the number of currently available tokens.
+// Negative values mean tokens are reserved by waiting callers.
+func (l *Limiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.tokens
+}
+
+// Adds tokens for the time passed since the last refill.
+// Must be called with the mutex held.
+func (l *Limiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		l.tokens = min(l.tokens, l.burst)
+	}
+	l.last = now
+}
+
+// Keyed holds a separate limiter for each key, such as a client ID.
+// Limiters that were not used for a while are evicted.
+// Methods are safe for concurrent use.
+type Keyed[K comparable] struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     int
+	idle      time.Duration
+	m         map[K]*keyedEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// A limiter with its last use time.
+type keyedEntry struct {
+	l    *Limiter
+	used time.Time
+}
+
+// NewKeyed returns a keyed limiter whose limiters are created with the
+// given rate and burst, and are evicted after being unused for idle.
+// idle should be long enough for limiters to refill, otherwise evicting
+// them resets their tokens early.
+func NewKeyed[K comparable](rate float64, burst int,
+	idle time.Duration) *Keyed[K] {
+	New(rate, burst) // Check parameters.
+	if idle <= 0 {
+		panic(fmt.Sprintf("bad idle: %v", idle))
+	}
+	return &Keyed[K]{rate: rate, burst: burst, idle: idle,
+		m: map[K]*keyedEntry{}, now: time.Now}
+}
+
+// Allow reports whether an event may happen now for k, and if so takes a
+// token from k's limiter.
+func (k *Keyed[K]) Allow(key K) bool {
+	return k.get(key).Allow()
+}
+
+// Wait blocks until an event may happen for k, and takes a token from k's
+// limiter. Returns ctx's error if it is done before then.
+func (k *Keyed[K]) Wait(ctx context.Context, key K) error {
+	return k.get(key).Wait(ctx)
+}
+
+// Len returns the number of limiters currently held.
+func (k *Keyed[K]) Len() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return len(k.m)
+}
+
+// Returns the limiter of key, creating it if needed.
+// Evicts idle limiters.
+func (k *Keyed[K]) get(key K) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := k.now()
+	if now.Sub(k.lastSweep) >= k.idle {
+		for kk, e := range k.m {
+			if now.Sub(e.used) >= k.idle {
+				delete(k.m, kk)
+			}
+		}
+		k.lastSweep = now
+	}
+	e, ok := k.m[key]
+	if !ok {
+		e = &keyedEntry{l: New(k.rate, k.burst)}
+		e.l.now = k.now
+		k.m[key] = e
+	}
+	e.used = now
+	return e.l
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// A fake clock for tests.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+func newClock() *clock                   { return &clock{time.Now()} }
+
+func TestAllow(t *testing.T) {
+	c := newClock()
+	l := New(10, 3)
+	l.now = c.now
+	for i := range 3 {
+		if !l.Allow() {
+			t.Fatalf("Allow() #%v=false, want true", i)
+		}
+	}
+	if l.Allow() {
+		t.Fatalf("Allow() after burst=true, want false")
+	}
+	c.advance(100 * time.Millisecond)
+	if !l.Allow() {
+		t.Fatalf("Allow() after refill=false, want true")
+	}
+	if l.Allow() {
+		t.Fatalf("Allow()=true, want false")
+	}
+	c.advance(time.Hour)
+	if got := l.Tokens(); got != 3 {
+		t.Fatalf("Tokens()=%v, want 3", got)
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := New(100, 1)
+	start := time.Now()
+	for range 6 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait(...) failed: %v", err)
+		}
+	}
+	// First is immediate, the rest take 10ms each.
+	if d := time.Since(start); d < 45*time.Millisecond || d > 200*time.Millisecond {
+		t.Fatalf("Wait x6 took %v, want about 50ms", d)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	l := New(1, 1)
+	l.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Wait(...)=%v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := l.Tokens(); got < -0.1 || got > 0.1 {
+		t.Fatalf("Tokens()=%v, want about 0", got)
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	c := newClock()
+	k := NewKeyed[string](1, 1, time.Minute)
+	k.now = c.now
+	if !k.Allow("a") || !k.Allow("b") {
+		t.Fatalf("Allow() for new keys=false, want true")
+	}
+	if k.Allow("a") {
+		t.Fatalf("Allow(a) again=true, want false")
+	}
+	if k.Len() != 2 {
+		t.Fatalf("Len()=%v, want 2", k.Len())
+	}
+	c.advance(30 * time.Second)
+	k.Allow("a")
+	c.advance(40 * time.Second)
+	k.Allow("c") // Triggers eviction of b.
+	if k.Len() != 2 {
+		t.Fatalf("Len()=%v, want 2", k.Len())
+	}
+	if _, ok := k.m["b"]; ok {
+		t.Fatalf("idle key b was not evicted")
+	}
+}
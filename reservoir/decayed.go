@@ -0,0 +1,94 @@
+package reservoir
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/fluhus/gostuff/heaps"
+)
+
+// Decayed samples a fixed number of elements from a stream, biased toward
+// recent elements. An element's weight decays exponentially with its age,
+// halving every half-life.
+//
+// Uses forward decay, so weights are computed relative to a fixed landmark
+// time and do not need to be updated as time passes. Elements may be added
+// out of order.
+//
+// Citation: Cormode, Shkapenyuk, Srivastava, Xu (2009). "Forward Decay: A
+// Practical Time Decay Model for Streaming Systems". ICDE.
+type Decayed[T any] struct {
+	h        *heaps.Heap[decayedItem[T]]
+	n        int
+	alpha    float64 // Decay rate per second.
+	landmark time.Time
+	started  bool
+	r        *rand.Rand
+}
+
+// A sampled element with its priority key.
+type decayedItem[T any] struct {
+	v   T
+	ts  time.Time
+	key float64
+}
+
+// NewDecayed returns a new sampler that samples n elements, with weights
+// that halve every halfLife.
+func NewDecayed[T any](n int, halfLife time.Duration) *Decayed[T] {
+	if n < 1 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	if halfLife <= 0 {
+		panic(fmt.Sprintf("bad halfLife: %v", halfLife))
+	}
+	return &Decayed[T]{
+		h: heaps.New(func(a, b decayedItem[T]) bool {
+			return a.key < b.key
+		}),
+		n:     n,
+		alpha: math.Ln2 / halfLife.Seconds(),
+		r:     rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// Add maybe adds x, which occurred at time ts, to the selected sample.
+func (s *Decayed[T]) Add(x T, ts time.Time) {
+	if !s.started {
+		s.landmark = ts
+		s.started = true
+	}
+	// Weighted sampling keeps the elements with the highest u^(1/w), for
+	// uniform u. This is the log of the log of that key, which keeps the
+	// same order without overflowing.
+	u := 1 - s.r.Float64() // In (0,1].
+	key := s.alpha*ts.Sub(s.landmark).Seconds() - math.Log(-math.Log(u))
+	if s.h.Len() == s.n {
+		if key <= s.h.Head().key {
+			return
+		}
+		s.h.Pop()
+	}
+	s.h.Push(decayedItem[T]{x, ts, key})
+}
+
+// Len returns the number of sampled elements.
+func (s *Decayed[T]) Len() int {
+	return s.h.Len()
+}
+
+// Snapshot returns the sampled elements, ordered by time.
+func (s *Decayed[T]) Snapshot() []T {
+	items := slices.Clone(s.h.View())
+	slices.SortStableFunc(items, func(a, b decayedItem[T]) int {
+		return a.ts.Compare(b.ts)
+	})
+	result := make([]T, len(items))
+	for i, item := range items {
+		result[i] = item.v
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package reservoir
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecayed_uniform(t *testing.T) {
+	// With a very long half-life, sampling should be close to uniform.
+	const n = 100
+	cnt := make([]int, n)
+	start := time.Now()
+	for range 10000 {
+		s := NewDecayed[int](10, time.Hour*24*365)
+		for i := range n {
+			s.Add(i, start.Add(time.Duration(i)*time.Second))
+		}
+		for _, i := range s.Snapshot() {
+			cnt[i]++
+		}
+	}
+	want1, want2 := 850, 1150
+	for i, c := range cnt {
+		if c < want1 || c > want2 {
+			t.Errorf("count(%v)=%v, want %v-%v", i, c, want1, want2)
+		}
+	}
+}
+
+func TestDecayed_recent(t *testing.T) {
+	// Elements one half-life apart should be chosen about half as often.
+	const n = 4
+	cnt := make([]int, n)
+	start := time.Now()
+	for range 20000 {
+		s := NewDecayed[int](1, time.Minute)
+		for i := range n {
+			s.Add(i, start.Add(time.Duration(i)*time.Minute))
+		}
+		cnt[s.Snapshot()[0]]++
+	}
+	// Weights are 1,2,4,8 out of 15.
+	for i, c := range cnt {
+		want := 20000.0 * float64(int(1)<<i) / 15
+		if float64(c) < want*0.9 || float64(c) > want*1.1 {
+			t.Errorf("count(%v)=%v, want about %v", i, c, want)
+		}
+	}
+}
+
+func TestDecayed_snapshot(t *testing.T) {
+	s := NewDecayed[int](5, time.Minute)
+	start := time.Now()
+	for i := range 3 {
+		s.Add(i, start.Add(-time.Duration(i)*time.Second))
+	}
+	got := s.Snapshot()
+	if len(got) != 3 || got[0] != 2 || got[1] != 1 || got[2] != 0 {
+		t.Errorf("Snapshot()=%v, want [2 1 0]", got)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len()=%v, want 3", s.Len())
+	}
+}
@@ -0,0 +1,152 @@
+// Package retry provides retrying of failing operations with exponential
+// backoff.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy determines how an operation is retried.
+// The zero value retries all errors indefinitely, starting with a delay of
+// 100 milliseconds and doubling it after each attempt.
+type Policy struct {
+	// Maximal number of attempts, including the first one.
+	// Zero means no limit.
+	MaxAttempts int
+
+	// Maximal time since the first attempt, after which no more attempts
+	// are made. Zero means no limit.
+	MaxElapsed time.Duration
+
+	// Delay after the first failed attempt. Zero means 100 milliseconds.
+	InitialDelay time.Duration
+
+	// Maximal delay between attempts. Zero means no limit.
+	MaxDelay time.Duration
+
+	// Factor by which the delay grows after each attempt.
+	// Zero means 2.
+	Multiplier float64
+
+	// Fraction of random variation in each delay, between 0 and 1.
+	// A delay d becomes a uniformly random duration in [d-j*d, d+j*d].
+	Jitter float64
+
+	// Reports whether an error should be retried. Nil means all errors
+	// are retried, except ones wrapped with [Permanent].
+	Retryable func(error) bool
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, or the
+// policy's limits are reached. Returns nil on success, or the last error
+// otherwise. If ctx is canceled while waiting, returns ctx's error joined
+// with the last error.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error,
+) error {
+	_, err := DoValue(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is like [Do], for functions that return a value.
+func DoValue[T any](ctx context.Context, p Policy,
+	fn func(ctx context.Context) (T, error)) (T, error) {
+	if err := p.check(); err != nil {
+		panic(err)
+	}
+	start := time.Now()
+	delay := float64(p.InitialDelay)
+	if delay == 0 {
+		delay = float64(100 * time.Millisecond)
+	}
+	mult := p.Multiplier
+	if mult == 0 {
+		mult = 2
+	}
+
+	for attempt := 1; ; attempt++ {
+		t, err := fn(ctx)
+		if err == nil {
+			return t, nil
+		}
+		if !p.retryable(err) {
+			return t, err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return t, err
+		}
+
+		d := delay
+		if p.MaxDelay > 0 {
+			d = math.Min(d, float64(p.MaxDelay))
+		}
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+		wait := time.Duration(d)
+		if p.MaxElapsed > 0 && time.Since(start)+wait > p.MaxElapsed {
+			return t, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return t, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay *= mult
+	}
+}
+
+// Returns whether err should be retried.
+func (p Policy) retryable(err error) bool {
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// Returns an error if the policy's fields are invalid.
+func (p Policy) check() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("bad MaxAttempts: %d", p.MaxAttempts)
+	}
+	if p.MaxElapsed < 0 || p.InitialDelay < 0 || p.MaxDelay < 0 {
+		return fmt.Errorf("negative durations in policy: %+v", p)
+	}
+	if p.Multiplier != 0 && p.Multiplier < 1 {
+		return fmt.Errorf("bad Multiplier: %v, want at least 1", p.Multiplier)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("bad Jitter: %v, want 0-1", p.Jitter)
+	}
+	return nil
+}
+
+// Permanent wraps err so that it is not retried.
+// Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// An error that should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
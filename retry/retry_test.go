@@ -0,0 +1,118 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+func TestDoSuccess(t *testing.T) {
+	calls := 0
+	p := Policy{InitialDelay: time.Millisecond}
+	got, err := DoValue(context.Background(), p,
+		func(ctx context.Context) (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, errTest
+			}
+			return 42, nil
+		})
+	if got != 42 || err != nil {
+		t.Fatalf("DoValue(...)=%v,%v, want 42,nil", got, err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls=%v, want 3", calls)
+	}
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 4, InitialDelay: time.Millisecond}
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errTest
+	})
+	if err != errTest || calls != 4 {
+		t.Fatalf("Do(...)=%v with %v calls, want %v with 4 calls",
+			err, calls, errTest)
+	}
+}
+
+func TestDoPermanent(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), Policy{}, func(ctx context.Context) error {
+		calls++
+		return Permanent(errTest)
+	})
+	if !errors.Is(err, errTest) || calls != 1 {
+		t.Fatalf("Do(...)=%v with %v calls, want %v with 1 call",
+			err, calls, errTest)
+	}
+}
+
+func TestDoRetryable(t *testing.T) {
+	calls := 0
+	other := errors.New("other")
+	p := Policy{
+		InitialDelay: time.Millisecond,
+		Retryable:    func(err error) bool { return err == errTest },
+	}
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errTest
+		}
+		return other
+	})
+	if err != other || calls != 3 {
+		t.Fatalf("Do(...)=%v with %v calls, want %v with 3 calls",
+			err, calls, other)
+	}
+}
+
+func TestDoBackoff(t *testing.T) {
+	var times []time.Time
+	p := Policy{MaxAttempts: 4, InitialDelay: 10 * time.Millisecond,
+		Multiplier: 2, MaxDelay: 30 * time.Millisecond}
+	Do(context.Background(), p, func(ctx context.Context) error {
+		times = append(times, time.Now())
+		return errTest
+	})
+	want := []time.Duration{10, 20, 30}
+	for i := range want {
+		d := times[i+1].Sub(times[i])
+		w := want[i] * time.Millisecond
+		if d < w || d > w+20*time.Millisecond {
+			t.Errorf("delay #%v=%v, want about %v", i, d, w)
+		}
+	}
+}
+
+func TestDoMaxElapsed(t *testing.T) {
+	calls := 0
+	p := Policy{MaxElapsed: 35 * time.Millisecond,
+		InitialDelay: 10 * time.Millisecond}
+	err := Do(context.Background(), p, func(ctx context.Context) error {
+		calls++
+		return errTest
+	})
+	// Attempts at 0, 10 and 30ms. The next one would be at 70ms.
+	if err != errTest || calls != 3 {
+		t.Fatalf("Do(...)=%v with %v calls, want %v with 3 calls",
+			err, calls, errTest)
+	}
+}
+
+func TestDoCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(),
+		20*time.Millisecond)
+	defer cancel()
+	err := Do(ctx, Policy{InitialDelay: time.Hour},
+		func(ctx context.Context) error { return errTest })
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errTest) {
+		t.Fatalf("Do(...)=%v, want deadline and test errors", err)
+	}
+}
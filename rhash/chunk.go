@@ -0,0 +1,96 @@
+// Content-defined chunking.
+
+package rhash
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+)
+
+// Window size for chunk boundary detection.
+const chunkWindow = 48
+
+// A Chunker splits a stream into content-defined chunks.
+//
+// Chunk boundaries are placed where the rolling hash of the last few bytes
+// matches a pattern, so that boundaries depend only on local content.
+// An insertion or deletion in the stream therefore changes only the chunks
+// around it, which makes chunks useful for deduplication.
+type Chunker struct {
+	r        io.ByteReader
+	h        *Buz
+	min, max int
+	mask     uint64
+	done     bool
+}
+
+// NewChunker returns a chunker that splits r into chunks of at least
+// minSize and at most maxSize bytes, with an average of roughly avgSize.
+// The last chunk may be shorter than minSize.
+func NewChunker(r io.Reader, minSize, avgSize, maxSize int) *Chunker {
+	if minSize < 1 || minSize > avgSize || avgSize > maxSize {
+		panic(fmt.Sprintf("bad sizes: min=%d avg=%d max=%d, want 0<min<=avg<=max",
+			minSize, avgSize, maxSize))
+	}
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	// Boundaries appear every 2^nbits bytes on average after minSize.
+	nbits := bits.Len(uint(max(avgSize-minSize, 1))) - 1
+	return &Chunker{
+		r:    br,
+		h:    NewBuz(chunkWindow),
+		min:  minSize,
+		max:  maxSize,
+		mask: 1<<nbits - 1,
+	}
+}
+
+// Next returns the next chunk.
+// Returns io.EOF after the last chunk.
+// The returned slice is newly allocated and may be retained by the caller.
+func (c *Chunker) Next() ([]byte, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	c.h.Reset()
+	var chunk []byte
+	for len(chunk) < c.max {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			c.done = true
+			if err == io.EOF && len(chunk) > 0 {
+				return chunk, nil
+			}
+			return nil, err
+		}
+		chunk = append(chunk, b)
+		c.h.WriteByte(b)
+		if len(chunk) >= c.min && c.h.Sum64()&c.mask == c.mask {
+			break
+		}
+	}
+	return chunk, nil
+}
+
+// Chunks iterates over content-defined chunks of r.
+// See [NewChunker] for the meaning of the parameters.
+func Chunks(r io.Reader, minSize, avgSize, maxSize int,
+) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		c := NewChunker(r, minSize, avgSize, maxSize)
+		for {
+			chunk, err := c.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package rhash
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/fluhus/gostuff/sets"
+)
+
+func TestChunks(t *testing.T) {
+	data := make([]byte, 1<<20)
+	rng := rand.NewChaCha8([32]byte{})
+	rng.Read(data)
+
+	var chunks [][]byte
+	for chunk, err := range Chunks(bytes.NewReader(data), 256, 1024, 4096) {
+		if err != nil {
+			t.Fatalf("Chunks(...) failed: %v", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	if got := bytes.Join(chunks, nil); !bytes.Equal(got, data) {
+		t.Fatalf("Chunks(...) joined chunks differ from input")
+	}
+	for i, c := range chunks {
+		if len(c) > 4096 || (len(c) < 256 && i < len(chunks)-1) {
+			t.Fatalf("Chunks(...) chunk #%d len=%v, want 256-4096", i, len(c))
+		}
+	}
+	avg := len(data) / len(chunks)
+	if avg < 700 || avg > 2000 {
+		t.Fatalf("Chunks(...) average size=%v, want ~1024", avg)
+	}
+}
+
+func TestChunks_shift(t *testing.T) {
+	data := make([]byte, 1<<18)
+	rng := rand.NewChaCha8([32]byte{1})
+	rng.Read(data)
+	shifted := slices.Concat([]byte("some prefix"), data)
+
+	collect := func(d []byte) sets.Set[string] {
+		s := sets.Set[string]{}
+		for chunk := range Chunks(bytes.NewReader(d), 64, 512, 2048) {
+			s.Add(string(chunk))
+		}
+		return s
+	}
+	a, b := collect(data), collect(shifted)
+	common := len(a.Intersect(b))
+	if common < len(a)-2 {
+		t.Fatalf("Chunks(...) common chunks=%v, want at least %v",
+			common, len(a)-2)
+	}
+}
+
+func TestChunks_empty(t *testing.T) {
+	for chunk := range Chunks(bytes.NewReader(nil), 1, 2, 3) {
+		t.Fatalf("Chunks(empty) yielded %q, want nothing", chunk)
+	}
+}
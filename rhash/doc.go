@@ -3,4 +3,6 @@
 // A rolling-hash is a hash function that "remembers" only the last n bytes it
 // received, where n is a parameter. Meaning, the hash of a byte sequence
 // always equals the hash of its last n bytes.
+//
+// The package also provides content-defined chunking based on a rolling hash.
 package rhash
@@ -0,0 +1,110 @@
+// Package segtree provides a segment tree for range queries.
+//
+// A segment tree maintains an aggregate (sum, min, max or any other
+// associative operation) over an array, with O(log n) point updates and
+// range queries.
+package segtree
+
+import (
+	"fmt"
+
+	"github.com/fluhus/gostuff/gnum"
+)
+
+// A Tree is a segment tree over a fixed number of elements.
+type Tree[T any] struct {
+	a  []T // a[n:] holds the elements, a[i] aggregates a[2i] and a[2i+1].
+	n  int
+	op func(T, T) T
+}
+
+// New returns a tree over the values of s, that aggregates values using op.
+// op must be associative, but not necessarily commutative.
+func New[S ~[]T, T any](s S, op func(T, T) T) *Tree[T] {
+	n := len(s)
+	a := make([]T, 2*n)
+	copy(a[n:], s)
+	t := &Tree[T]{a, n, op}
+	for i := n - 1; i > 0; i-- {
+		a[i] = op(a[2*i], a[2*i+1])
+	}
+	return t
+}
+
+// NewSum returns a tree over the values of s, that calculates range sums.
+func NewSum[S ~[]N, N gnum.Number](s S) *Tree[N] {
+	return New(s, func(a, b N) N { return a + b })
+}
+
+// NewMin returns a tree over the values of s, that calculates range minima.
+func NewMin[S ~[]N, N gnum.Number](s S) *Tree[N] {
+	return New(s, func(a, b N) N { return min(a, b) })
+}
+
+// NewMax returns a tree over the values of s, that calculates range maxima.
+func NewMax[S ~[]N, N gnum.Number](s S) *Tree[N] {
+	return New(s, func(a, b N) N { return max(a, b) })
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree[T]) Len() int {
+	return t.n
+}
+
+// Get returns the value of the i'th element.
+func (t *Tree[T]) Get(i int) T {
+	t.checkIndex(i)
+	return t.a[t.n+i]
+}
+
+// Set sets the value of the i'th element.
+func (t *Tree[T]) Set(i int, v T) {
+	t.checkIndex(i)
+	i += t.n
+	t.a[i] = v
+	for i /= 2; i > 0; i /= 2 {
+		t.a[i] = t.op(t.a[2*i], t.a[2*i+1])
+	}
+}
+
+// Query returns the aggregate of elements i (inclusive) through
+// j (exclusive). Panics if the range is empty.
+func (t *Tree[T]) Query(i, j int) T {
+	if i < 0 || j > t.n || i >= j {
+		panic(fmt.Sprintf("bad range [%d,%d) for length %d", i, j, t.n))
+	}
+	var left, right T
+	hasLeft, hasRight := false, false
+	for i, j = i+t.n, j+t.n; i < j; i, j = i/2, j/2 {
+		if i%2 == 1 {
+			if hasLeft {
+				left = t.op(left, t.a[i])
+			} else {
+				left, hasLeft = t.a[i], true
+			}
+			i++
+		}
+		if j%2 == 1 {
+			j--
+			if hasRight {
+				right = t.op(t.a[j], right)
+			} else {
+				right, hasRight = t.a[j], true
+			}
+		}
+	}
+	if !hasLeft {
+		return right
+	}
+	if !hasRight {
+		return left
+	}
+	return t.op(left, right)
+}
+
+// Panics if i is not a valid element index.
+func (t *Tree[T]) checkIndex(i int) {
+	if i < 0 || i >= t.n {
+		panic(fmt.Sprintf("index %d out of range [0,%d)", i, t.n))
+	}
+}
@@ -0,0 +1,52 @@
+package segtree
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/fluhus/gostuff/gnum"
+)
+
+func TestRandom(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 16, 100} {
+		a := make([]int, n)
+		for i := range a {
+			a[i] = rand.IntN(1000)
+		}
+		sum, mn, mx := NewSum(a), NewMin(a), NewMax(a)
+		for range 500 {
+			i := rand.IntN(n)
+			v := rand.IntN(1000)
+			a[i] = v
+			sum.Set(i, v)
+			mn.Set(i, v)
+			mx.Set(i, v)
+
+			j := rand.IntN(n)
+			k := j + 1 + rand.IntN(n-j)
+			if got, want := sum.Query(j, k), gnum.Sum(a[j:k]); got != want {
+				t.Fatalf("sum.Query(%v,%v)=%v, want %v", j, k, got, want)
+			}
+			if got, want := mn.Query(j, k), gnum.Min(a[j:k]); got != want {
+				t.Fatalf("min.Query(%v,%v)=%v, want %v", j, k, got, want)
+			}
+			if got, want := mx.Query(j, k), gnum.Max(a[j:k]); got != want {
+				t.Fatalf("max.Query(%v,%v)=%v, want %v", j, k, got, want)
+			}
+		}
+	}
+}
+
+func TestNonCommutative(t *testing.T) {
+	input := strings.Split("abcdefghijk", "")
+	tr := New(input, func(a, b string) string { return a + b })
+	for i := range input {
+		for j := i + 1; j <= len(input); j++ {
+			want := strings.Join(input[i:j], "")
+			if got := tr.Query(i, j); got != want {
+				t.Errorf("Query(%v,%v)=%q, want %q", i, j, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package shardmap provides a concurrent map that is sharded across
+// multiple locks.
+//
+// Each key belongs to a single shard, so operations on keys in different
+// shards do not block each other. This performs well under high write
+// rates, where [sync.Map] does not.
+package shardmap
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"sync"
+)
+
+// A Map is a concurrent map. Methods are safe for concurrent use.
+type Map[K comparable, V any] struct {
+	shards []shard[K, V]
+	seed   maphash.Seed
+	mask   uint64
+}
+
+// A single lock-protected part of a map.
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// New returns an empty map with the given number of shards,
+// rounded up to a power of 2.
+func New[K comparable, V any](nshards int) *Map[K, V] {
+	if nshards < 1 {
+		panic(fmt.Sprintf("bad nshards: %d", nshards))
+	}
+	n := 1
+	for n < nshards {
+		n *= 2
+	}
+	shards := make([]shard[K, V], n)
+	for i := range shards {
+		shards[i].m = map[K]V{}
+	}
+	return &Map[K, V]{
+		shards: shards,
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(n - 1),
+	}
+}
+
+// Get returns the value of k and true, or the zero value and false if
+// k is not in the map.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	s := m.shard(k)
+	s.RLock()
+	v, ok := s.m[k]
+	s.RUnlock()
+	return v, ok
+}
+
+// Set sets the value of k to v.
+func (m *Map[K, V]) Set(k K, v V) {
+	s := m.shard(k)
+	s.Lock()
+	s.m[k] = v
+	s.Unlock()
+}
+
+// Delete removes k from the map. Returns true if k was in the map.
+func (m *Map[K, V]) Delete(k K) bool {
+	s := m.shard(k)
+	s.Lock()
+	_, ok := s.m[k]
+	delete(s.m, k)
+	s.Unlock()
+	return ok
+}
+
+// GetOrCompute returns the value of k if it exists. Otherwise, it sets
+// the value of k to the result of f and returns it. The returned bool is
+// true if the value was already in the map.
+//
+// f is called at most once per missing key, while holding the lock of
+// k's shard, so it should not access the map.
+func (m *Map[K, V]) GetOrCompute(k K, f func() V) (V, bool) {
+	s := m.shard(k)
+	s.RLock()
+	v, ok := s.m[k]
+	s.RUnlock()
+	if ok {
+		return v, true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if v, ok := s.m[k]; ok { // Another goroutine got here first.
+		return v, true
+	}
+	v = f()
+	s.m[k] = v
+	return v, false
+}
+
+// Update atomically updates the value of k. f is called with the current
+// value of k and whether it exists, and returns the new value and whether
+// to keep it. If keep is false, k is removed from the map.
+//
+// f is called while holding the lock of k's shard, so it should not access
+// the map.
+func (m *Map[K, V]) Update(k K, f func(v V, ok bool) (newv V, keep bool)) {
+	s := m.shard(k)
+	s.Lock()
+	defer s.Unlock()
+	v, ok := s.m[k]
+	v, keep := f(v, ok)
+	if keep {
+		s.m[k] = v
+	} else {
+		delete(s.m, k)
+	}
+}
+
+// Len returns the number of elements in the map.
+// Shards are counted one at a time, so concurrent modifications may or may
+// not be reflected in the result.
+func (m *Map[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		n += len(s.m)
+		s.RUnlock()
+	}
+	return n
+}
+
+// All returns an iterator over the map's elements, in no particular order.
+// Each shard is copied before its elements are yielded, so the map can be
+// modified during iteration. Modifications to shards that were not yet
+// copied are reflected in the iteration.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var keys []K
+		var vals []V
+		for i := range m.shards {
+			keys, vals = keys[:0], vals[:0]
+			s := &m.shards[i]
+			s.RLock()
+			for k, v := range s.m {
+				keys = append(keys, k)
+				vals = append(vals, v)
+			}
+			s.RUnlock()
+			for j := range keys {
+				if !yield(keys[j], vals[j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Returns the shard that k belongs to.
+func (m *Map[K, V]) shard(k K) *shard[K, V] {
+	return &m.shards[maphash.Comparable(m.seed, k)&m.mask]
+}
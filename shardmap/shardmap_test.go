@@ -0,0 +1,109 @@
+package shardmap
+
+import (
+	"maps"
+	"sync"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	m := New[string, int](5)
+	if len(m.shards) != 8 {
+		t.Fatalf("len(shards)=%v, want 8", len(m.shards))
+	}
+	want := map[string]int{}
+	for i, s := range []string{"a", "b", "c", "d", "a", "e", "b"} {
+		m.Set(s, i)
+		want[s] = i
+	}
+	if m.Len() != len(want) {
+		t.Fatalf("Len()=%v, want %v", m.Len(), len(want))
+	}
+	for k, v := range want {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Errorf("Get(%q)=%v,%v, want %v,true", k, got, ok, v)
+		}
+	}
+	if got, ok := m.Get("x"); ok {
+		t.Errorf("Get(%q)=%v,%v, want 0,false", "x", got, ok)
+	}
+	if !m.Delete("a") {
+		t.Errorf("Delete(%q)=false, want true", "a")
+	}
+	if m.Delete("a") {
+		t.Errorf("Delete(%q)=true, want false", "a")
+	}
+	delete(want, "a")
+	if got := maps.Collect(m.All()); !maps.Equal(got, want) {
+		t.Errorf("All()=%v, want %v", got, want)
+	}
+}
+
+func TestGetOrCompute(t *testing.T) {
+	m := New[int, int](4)
+	calls := 0
+	f := func() int { calls++; return 10 }
+	if v, ok := m.GetOrCompute(1, f); v != 10 || ok {
+		t.Errorf("GetOrCompute(1)=%v,%v, want 10,false", v, ok)
+	}
+	if v, ok := m.GetOrCompute(1, f); v != 10 || !ok {
+		t.Errorf("GetOrCompute(1)=%v,%v, want 10,true", v, ok)
+	}
+	if calls != 1 {
+		t.Errorf("calls=%v, want 1", calls)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	m := New[int, int](4)
+	inc := func(v int, ok bool) (int, bool) { return v + 1, true }
+	m.Update(1, inc)
+	m.Update(1, inc)
+	if v, _ := m.Get(1); v != 2 {
+		t.Errorf("Get(1)=%v, want 2", v)
+	}
+	m.Update(1, func(v int, ok bool) (int, bool) { return 0, false })
+	if _, ok := m.Get(1); ok {
+		t.Errorf("Get(1) found deleted key")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	m := New[int, int](16)
+	const n, per = 8, 1000
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range per {
+				m.Update(i%100, func(v int, ok bool) (int, bool) {
+					return v + 1, true
+				})
+				m.GetOrCompute(1000+i, func() int { return i })
+			}
+		}()
+	}
+	wg.Wait()
+	for i := range 100 {
+		if v, _ := m.Get(i); v != n*per/100 {
+			t.Fatalf("Get(%v)=%v, want %v", i, v, n*per/100)
+		}
+	}
+	if m.Len() != 100+per {
+		t.Fatalf("Len()=%v, want %v", m.Len(), 100+per)
+	}
+}
+
+func TestModifyWhileIterating(t *testing.T) {
+	m := New[int, int](4)
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	for k := range m.All() {
+		m.Delete(k)
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len()=%v, want 0", m.Len())
+	}
+}
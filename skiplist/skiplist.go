@@ -0,0 +1,262 @@
+// Package skiplist provides an ordered map implemented as a skip list.
+//
+// The map is a mutex-guarded skip list: it is safe for concurrent use, but
+// every insert and delete takes the same write lock, so writes do not run
+// in parallel. Iteration does not hold the lock while yielding, so the map
+// may be modified during iteration.
+//
+// In addition to the regular map operations, the map supports rank queries:
+// finding the index of a key in the sorted order and finding the key at a
+// given index, both in O(log n).
+package skiplist
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"sync"
+)
+
+const (
+	maxLevel = 32 // Maximal height of a node.
+	pLevel   = 4  // 1/p is the probability of raising a node's height.
+)
+
+// A Map is an ordered map implemented as a skip list, guarded by a single
+// read-write mutex.
+type Map[K cmp.Ordered, V any] struct {
+	head  *node[K, V]
+	n     int
+	level int // Current maximal level in use.
+	mu    sync.RWMutex
+}
+
+// A single node in the list.
+type node[K cmp.Ordered, V any] struct {
+	k     K
+	v     V
+	next  []*node[K, V]
+	width []int // Number of level-0 steps to next. Nil next means the end.
+}
+
+// New returns an empty map.
+func New[K cmp.Ordered, V any]() *Map[K, V] {
+	head := &node[K, V]{
+		next:  make([]*node[K, V], maxLevel),
+		width: make([]int, maxLevel),
+	}
+	for i := range head.width {
+		head.width[i] = 1
+	}
+	return &Map[K, V]{head: head, level: 1}
+}
+
+// Len returns the number of elements in the map.
+func (m *Map[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.n
+}
+
+// Get returns the value associated with k, and whether k was found.
+func (m *Map[K, V]) Get(k K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	xk, v, ok := m.find(k)
+	if !ok || xk != k {
+		var zero V
+		return zero, false
+	}
+	return v, true
+}
+
+// Set associates v with k.
+// Returns true if k is new in the map and false if an existing value
+// was replaced.
+func (m *Map[K, V]) Set(k K, v V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var update [maxLevel]*node[K, V]
+	var rank [maxLevel]int
+	x := m.head
+	for l := maxLevel - 1; l >= 0; l-- {
+		if l < maxLevel-1 {
+			rank[l] = rank[l+1]
+		}
+		for x.next[l] != nil && x.next[l].k < k {
+			rank[l] += x.width[l]
+			x = x.next[l]
+		}
+		update[l] = x
+	}
+	if next := x.next[0]; next != nil && next.k == k {
+		next.v = v
+		return false
+	}
+
+	lvl := randomLevel()
+	m.level = max(m.level, lvl)
+	nu := &node[K, V]{k, v, make([]*node[K, V], lvl), make([]int, lvl)}
+	pos := rank[0] + 1 // Position of the new node.
+	for l := range maxLevel {
+		u := update[l]
+		if l < lvl {
+			nu.next[l] = u.next[l]
+			nu.width[l] = u.width[l] - (pos - rank[l]) + 1
+			u.next[l] = nu
+			u.width[l] = pos - rank[l]
+		} else {
+			u.width[l]++
+		}
+	}
+	m.n++
+	return true
+}
+
+// Delete removes k from the map.
+// Returns whether k was found.
+func (m *Map[K, V]) Delete(k K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var update [maxLevel]*node[K, V]
+	x := m.head
+	for l := maxLevel - 1; l >= 0; l-- {
+		for x.next[l] != nil && x.next[l].k < k {
+			x = x.next[l]
+		}
+		update[l] = x
+	}
+	x = x.next[0]
+	if x == nil || x.k != k {
+		return false
+	}
+	for l, u := range update {
+		if u.next[l] == x {
+			u.width[l] += x.width[l] - 1
+			u.next[l] = x.next[l]
+		} else {
+			u.width[l]--
+		}
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	m.n--
+	return true
+}
+
+// Rank returns the number of keys in the map that are less than k.
+// If k is in the map, this is its 0-based index in the sorted order.
+func (m *Map[K, V]) Rank(k K) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pos := 0
+	x := m.head
+	for l := m.level - 1; l >= 0; l-- {
+		for x.next[l] != nil && x.next[l].k < k {
+			pos += x.width[l]
+			x = x.next[l]
+		}
+	}
+	return pos
+}
+
+// At returns the key and value at index i in the sorted order.
+func (m *Map[K, V]) At(i int) (K, V) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if i < 0 || i >= m.n {
+		panic(fmt.Sprintf("index %d out of range [0,%d)", i, m.n))
+	}
+	pos := 0
+	x := m.head
+	for l := m.level - 1; l >= 0; l-- {
+		for x.next[l] != nil && pos+x.width[l] <= i+1 {
+			pos += x.width[l]
+			x = x.next[l]
+		}
+	}
+	return x.k, x.v
+}
+
+// All iterates over the elements of the map in ascending key order.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mu.RLock()
+		k, v, ok := entry(m.head.next[0])
+		m.mu.RUnlock()
+		m.iterFrom(k, v, ok, yield)
+	}
+}
+
+// From iterates over the elements of the map whose keys are at least k,
+// in ascending key order.
+func (m *Map[K, V]) From(k K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.mu.RLock()
+		k, v, ok := m.find(k)
+		m.mu.RUnlock()
+		m.iterFrom(k, v, ok, yield)
+	}
+}
+
+// Yields elements starting at the given one. Re-seeks after each yield,
+// so that concurrent modifications are respected. Only copies taken under
+// the lock are yielded.
+func (m *Map[K, V]) iterFrom(k K, v V, ok bool, yield func(K, V) bool) {
+	for ok {
+		if !yield(k, v) {
+			return
+		}
+		m.mu.RLock()
+		k, v, ok = m.findAfter(k)
+		m.mu.RUnlock()
+	}
+}
+
+// Returns the first entry whose key is at least k, and false if there is
+// none. Caller should hold the lock.
+func (m *Map[K, V]) find(k K) (K, V, bool) {
+	x := m.head
+	for l := m.level - 1; l >= 0; l-- {
+		for x.next[l] != nil && x.next[l].k < k {
+			x = x.next[l]
+		}
+	}
+	return entry(x.next[0])
+}
+
+// Returns the first entry whose key is greater than k, and false if there
+// is none. Caller should hold the lock.
+func (m *Map[K, V]) findAfter(k K) (K, V, bool) {
+	x := m.head
+	for l := m.level - 1; l >= 0; l-- {
+		for x.next[l] != nil && x.next[l].k <= k {
+			x = x.next[l]
+		}
+	}
+	return entry(x.next[0])
+}
+
+// Returns copies of x's key and value, and false if x is nil.
+// Caller should hold the lock.
+func entry[K cmp.Ordered, V any](x *node[K, V]) (K, V, bool) {
+	if x == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return x.k, x.v, true
+}
+
+// Returns a random node height.
+func randomLevel() int {
+	lvl := 1
+	for lvl < maxLevel && rand.IntN(pLevel) == 0 {
+		lvl++
+	}
+	return lvl
+}
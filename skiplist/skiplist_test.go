@@ -0,0 +1,139 @@
+package skiplist
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	m := New[int, string]()
+	want := map[int]string{}
+	for range 5000 {
+		k := rand.IntN(500)
+		if rand.IntN(3) == 0 {
+			_, had := want[k]
+			if got := m.Delete(k); got != had {
+				t.Fatalf("Delete(%v)=%v, want %v", k, got, had)
+			}
+			delete(want, k)
+		} else {
+			v := fmt.Sprint(rand.Int())
+			_, had := want[k]
+			if got := m.Set(k, v); got == had {
+				t.Fatalf("Set(%v)=%v, want %v", k, got, !had)
+			}
+			want[k] = v
+		}
+	}
+	if m.Len() != len(want) {
+		t.Fatalf("Len()=%v, want %v", m.Len(), len(want))
+	}
+	for k, v := range want {
+		if got, ok := m.Get(k); !ok || got != v {
+			t.Fatalf("Get(%v)=%v,%v, want %v,true", k, got, ok, v)
+		}
+	}
+	if _, ok := m.Get(1000); ok {
+		t.Fatalf("Get(1000)=_,true, want false")
+	}
+
+	keys := slices.Sorted(maps.Keys(want))
+	var got []int
+	for k, v := range m.All() {
+		if v != want[k] {
+			t.Fatalf("All() yielded %v:%v, want %v:%v", k, v, k, want[k])
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, keys) {
+		t.Fatalf("All()=%v, want %v", got, keys)
+	}
+
+	for i, k := range keys {
+		if got := m.Rank(k); got != i {
+			t.Fatalf("Rank(%v)=%v, want %v", k, got, i)
+		}
+		if got, _ := m.At(i); got != k {
+			t.Fatalf("At(%v)=%v, want %v", i, got, k)
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	m := New[string, int]()
+	for i, k := range []string{"d", "b", "a", "f", "e"} {
+		m.Set(k, i)
+	}
+	var got []string
+	for k := range m.From("c") {
+		got = append(got, k)
+	}
+	if want := []string{"d", "e", "f"}; !slices.Equal(got, want) {
+		t.Fatalf("From(c)=%v, want %v", got, want)
+	}
+}
+
+func TestModifyWhileIterating(t *testing.T) {
+	m := New[int, int]()
+	for i := range 10 {
+		m.Set(i*2, i)
+	}
+	var got []int
+	for k := range m.All() {
+		got = append(got, k)
+		m.Delete(k + 2)
+		if k == 0 {
+			m.Set(1, 1)
+		}
+	}
+	if want := []int{0, 1, 4, 8, 12, 16}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	m := New[int, int]()
+	wg := &sync.WaitGroup{}
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				m.Set(i*8+g, i)
+				m.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if m.Len() != 8000 {
+		t.Fatalf("Len()=%v, want 8000", m.Len())
+	}
+	for i := range 8000 {
+		if got := m.Rank(i); got != i {
+			t.Fatalf("Rank(%v)=%v, want %v", i, got, i)
+		}
+	}
+}
+
+func TestSetWhileIterating(t *testing.T) {
+	m := New[int, int]()
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 10000 {
+			m.Set(i%100, i)
+		}
+	}()
+	for range 100 {
+		for range m.All() {
+		}
+	}
+	<-done
+}
@@ -0,0 +1,71 @@
+package snm
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Chunk splits s into consecutive subslices of length n.
+// The last subslice may be shorter. Subslices share s's memory, and are
+// clipped so that appending to one does not overwrite the next.
+func Chunk[S ~[]T, T any](s S, n int) []S {
+	if n < 1 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	result := make([]S, 0, (len(s)+n-1)/n)
+	for i := 0; i < len(s); i += n {
+		result = append(result, slices.Clip(s[i:min(i+n, len(s))]))
+	}
+	return result
+}
+
+// Window returns the subslices of s of length n, starting at every step'th
+// element. Subslices share s's memory, and are clipped so that appending
+// to one does not overwrite another. Returns nil if s is shorter than n.
+func Window[S ~[]T, T any](s S, n, step int) []S {
+	if n < 1 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	if step < 1 {
+		panic(fmt.Sprintf("bad step: %d", step))
+	}
+	var result []S
+	for i := 0; i+n <= len(s); i += step {
+		result = append(result, slices.Clip(s[i:i+n]))
+	}
+	return result
+}
+
+// A Pair holds two values.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip returns pairs of the corresponding elements of a and b.
+// The result has the length of the shorter input.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	return Slice(min(len(a), len(b)), func(i int) Pair[A, B] {
+		return Pair[A, B]{a[i], b[i]}
+	})
+}
+
+// Unzip returns the first and second elements of the given pairs.
+func Unzip[A, B any](p []Pair[A, B]) ([]A, []B) {
+	return SliceToSlice(p, func(x Pair[A, B]) A { return x.First }),
+		SliceToSlice(p, func(x Pair[A, B]) B { return x.Second })
+}
+
+// Partition returns the elements of s for which pred returns true and the
+// elements for which it returns false, each in their original order.
+func Partition[S ~[]T, T any](s S, pred func(T) bool) (S, S) {
+	var yes, no S
+	for _, x := range s {
+		if pred(x) {
+			yes = append(yes, x)
+		} else {
+			no = append(no, x)
+		}
+	}
+	return yes, no
+}
@@ -0,0 +1,75 @@
+package snm
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		s    []int
+		n    int
+		want [][]int
+	}{
+		{nil, 2, [][]int{}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2, 3}, 5, [][]int{{1, 2, 3}}},
+	}
+	for _, test := range tests {
+		got := Chunk(test.s, test.n)
+		if !slices.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("Chunk(%v,%v)=%v, want %v", test.s, test.n, got, test.want)
+		}
+	}
+
+	s := []int{1, 2, 3, 4}
+	c := Chunk(s, 2)
+	_ = append(c[0], 10)
+	if s[2] != 3 {
+		t.Errorf("append to chunk overwrote next chunk: %v", s)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		s       []int
+		n, step int
+		want    [][]int
+	}{
+		{[]int{1, 2}, 3, 1, nil},
+		{[]int{1, 2, 3, 4}, 2, 1, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+		{[]int{1, 2, 3, 4, 5}, 2, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2, 3, 4, 5}, 3, 2, [][]int{{1, 2, 3}, {3, 4, 5}}},
+	}
+	for _, test := range tests {
+		got := Window(test.s, test.n, test.step)
+		if !slices.EqualFunc(got, test.want, slices.Equal) {
+			t.Errorf("Window(%v,%v,%v)=%v, want %v",
+				test.s, test.n, test.step, got, test.want)
+		}
+	}
+}
+
+func TestZip(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := []string{"a", "b"}
+	got := Zip(a, b)
+	want := []Pair[int, string]{{1, "a"}, {2, "b"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Zip(%v,%v)=%v, want %v", a, b, got, want)
+	}
+	ga, gb := Unzip(got)
+	if !slices.Equal(ga, a[:2]) || !slices.Equal(gb, b) {
+		t.Fatalf("Unzip(%v)=%v,%v, want %v,%v", got, ga, gb, a[:2], b)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	yes, no := Partition(s, func(i int) bool { return i%3 == 0 })
+	if fmt.Sprint(yes, no) != "[3 6] [1 2 4 5 7]" {
+		t.Errorf("Partition(%v)=%v,%v, want [3 6],[1 2 4 5 7]", s, yes, no)
+	}
+}
@@ -67,3 +67,15 @@ func (q *Queue[T]) Seq() iter.Seq[T] {
 		}
 	}
 }
+
+// All returns an iterator over the queue's elements from first to last,
+// without modifying its contents.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range q.n {
+			if !yield(q.q[(q.i+i)%len(q.q)]) {
+				return
+			}
+		}
+	}
+}
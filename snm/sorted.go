@@ -0,0 +1,73 @@
+package snm
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fluhus/gostuff/heaps"
+	"github.com/fluhus/gostuff/sets"
+)
+
+// InsertSorted inserts x to the sorted slice s, keeping it sorted, and
+// returns the updated slice. x is inserted after elements equal to it.
+func InsertSorted[S ~[]T, T cmp.Ordered](s S, x T) S {
+	i, _ := slices.BinarySearch(s, x)
+	for i < len(s) && s[i] == x {
+		i++
+	}
+	return slices.Insert(s, i, x)
+}
+
+// RemoveSorted removes one occurrence of x from the sorted slice s, and
+// returns the updated slice and whether x was found.
+func RemoveSorted[S ~[]T, T cmp.Ordered](s S, x T) (S, bool) {
+	i, found := slices.BinarySearch(s, x)
+	if !found {
+		return s, false
+	}
+	return slices.Delete(s, i, i+1), true
+}
+
+// MergeSorted returns a sorted slice with the elements of all the given
+// sorted slices. Runs in O(n log k) time for n elements in k slices.
+func MergeSorted[S ~[]T, T cmp.Ordered](ss ...S) S {
+	n := 0
+	h := heaps.New(func(a, b S) bool { return a[0] < b[0] })
+	for _, s := range ss {
+		n += len(s)
+		if len(s) > 0 {
+			h.Push(s)
+		}
+	}
+	result := make(S, 0, n)
+	for h.Len() > 0 {
+		s := h.Pop()
+		result = append(result, s[0])
+		if len(s) > 1 {
+			h.Push(s[1:])
+		}
+	}
+	return result
+}
+
+// DedupeSorted removes consecutive duplicates from the sorted slice s, and
+// returns the updated slice.
+func DedupeSorted[S ~[]T, T comparable](s S) S {
+	return slices.Compact(s)
+}
+
+// IntersectSorted returns the distinct elements that appear in all the
+// given sorted slices, in ascending order.
+func IntersectSorted[S ~[]T, T cmp.Ordered](ss ...S) S {
+	if len(ss) == 0 {
+		return nil
+	}
+	// Start with the shortest slice to keep intermediate results small.
+	ss = slices.Clone(ss)
+	slices.SortFunc(ss, func(a, b S) int { return len(a) - len(b) })
+	result := DedupeSorted(slices.Clone(ss[0]))
+	for _, s := range ss[1:] {
+		result = sets.SortedIntersection(result, s)
+	}
+	return result
+}
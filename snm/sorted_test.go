@@ -0,0 +1,81 @@
+package snm
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestInsertRemoveSorted(t *testing.T) {
+	var s []int
+	var want []int
+	for range 200 {
+		x := rand.IntN(50)
+		if rand.IntN(3) == 0 {
+			var ok bool
+			s, ok = RemoveSorted(s, x)
+			i := slices.Index(want, x)
+			if ok != (i != -1) {
+				t.Fatalf("RemoveSorted(%v)=%v, want %v", x, ok, i != -1)
+			}
+			if i != -1 {
+				want = slices.Delete(want, i, i+1)
+			}
+		} else {
+			s = InsertSorted(s, x)
+			want = append(want, x)
+			slices.Sort(want)
+		}
+		if !slices.Equal(s, want) {
+			t.Fatalf("got %v, want %v", s, want)
+		}
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	tests := []struct {
+		input [][]int
+		want  []int
+	}{
+		{nil, []int{}},
+		{[][]int{{1, 3, 5}}, []int{1, 3, 5}},
+		{[][]int{{1, 4, 7}, {}, {2, 5, 8}, {3, 3, 6, 9, 10}},
+			[]int{1, 2, 3, 3, 4, 5, 6, 7, 8, 9, 10}},
+	}
+	for _, test := range tests {
+		if got := MergeSorted(test.input...); !slices.Equal(got, test.want) {
+			t.Errorf("MergeSorted(%v)=%v, want %v", test.input, got, test.want)
+		}
+	}
+}
+
+func TestDedupeSorted(t *testing.T) {
+	input := []string{"a", "a", "b", "c", "c", "c"}
+	want := []string{"a", "b", "c"}
+	if got := DedupeSorted(slices.Clone(input)); !slices.Equal(got, want) {
+		t.Errorf("DedupeSorted(%v)=%v, want %v", input, got, want)
+	}
+}
+
+func TestIntersectSorted(t *testing.T) {
+	tests := []struct {
+		input [][]int
+		want  []int
+	}{
+		{nil, nil},
+		{[][]int{{1, 1, 2}}, []int{1, 2}},
+		{[][]int{{1, 2, 2, 3, 5, 8}, {2, 2, 3, 4, 5}, {0, 2, 5, 9}},
+			[]int{2, 5}},
+		{[][]int{{1, 2}, {3, 4}}, []int{}},
+	}
+	for _, test := range tests {
+		input := SliceToSlice(test.input, slices.Clone)
+		got := IntersectSorted(test.input...)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("IntersectSorted(%v)=%v, want %v", input, got, test.want)
+		}
+		if !slices.EqualFunc(test.input, input, slices.Equal) {
+			t.Errorf("IntersectSorted(%v) modified input", input)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package snm
+
+import (
+	"iter"
+	"slices"
+)
+
+// Stack is a LIFO container. The zero value is an empty stack.
+type Stack[T any] struct {
+	s []T
+}
+
+// Push inserts an element to the top of the stack.
+func (s *Stack[T]) Push(x T) {
+	s.s = append(s.s, x)
+}
+
+// Pop removes and returns the top element of the stack.
+// Panics if the stack is empty.
+func (s *Stack[T]) Pop() T {
+	if len(s.s) == 0 {
+		panic("pop with 0 elements")
+	}
+	x := s.s[len(s.s)-1]
+	var zero T
+	s.s[len(s.s)-1] = zero // Remove element to allow GC.
+	s.s = s.s[:len(s.s)-1]
+	return x
+}
+
+// Peek returns the top element of the stack,
+// without modifying its contents.
+// Panics if the stack is empty.
+func (s *Stack[T]) Peek() T {
+	if len(s.s) == 0 {
+		panic("peek with 0 elements")
+	}
+	return s.s[len(s.s)-1]
+}
+
+// Len return the current number of elements in the stack.
+func (s *Stack[T]) Len() int {
+	return len(s.s)
+}
+
+// Seq returns an iterator over the stack's elements,
+// popping each one.
+//
+// It is okay to push elements while iterating,
+// from within the same goroutine.
+// The new elements will be included in the same loop.
+func (s *Stack[T]) Seq() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s.Len() > 0 {
+			if !yield(s.Pop()) {
+				break
+			}
+		}
+	}
+}
+
+// All returns an iterator over the stack's elements from top to bottom,
+// without modifying its contents.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, x := range slices.Backward(s.s) {
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package snm
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	s := &Stack[int]{}
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+	if got := s.Peek(); got != 3 {
+		t.Fatalf("Peek()=%v, want 3", got)
+	}
+	if got, want := slices.Collect(s.All()), []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+	if got := s.Pop(); got != 3 {
+		t.Fatalf("Pop()=%v, want 3", got)
+	}
+	s.Push(4)
+	if s.Len() != 3 {
+		t.Fatalf("Len()=%v, want 3", s.Len())
+	}
+	if got, want := slices.Collect(s.Seq()), []int{4, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("Seq()=%v, want %v", got, want)
+	}
+	if s.Len() != 0 {
+		t.Fatalf("Len()=%v, want 0", s.Len())
+	}
+}
+
+func TestStackPopEmpty(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Pop() on empty stack did not panic")
+		}
+	}()
+	(&Stack[int]{}).Pop()
+}
+
+func TestQueueAll(t *testing.T) {
+	q := &Queue[int]{}
+	for i := range 5 {
+		q.Enqueue(i)
+	}
+	q.Dequeue()
+	q.Dequeue()
+	q.Enqueue(5)
+	if got, want := slices.Collect(q.All()), []int{2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("All()=%v, want %v", got, want)
+	}
+	if q.Len() != 4 {
+		t.Fatalf("Len()=%v, want 4", q.Len())
+	}
+}
@@ -0,0 +1,213 @@
+// Package sparse provides a sparse matrix in compressed sparse row (CSR)
+// format.
+//
+// A CSR matrix stores only its non-zero elements, row by row,
+// making row iteration and matrix-vector multiplication efficient.
+// The transpose of a CSR matrix is equivalent to the compressed sparse column
+// (CSC) format of the original matrix.
+package sparse
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+
+	"github.com/fluhus/gostuff/gnum"
+)
+
+// A Triplet is a single matrix element given by its coordinates.
+type Triplet[N gnum.Number] struct {
+	Row int
+	Col int
+	Val N
+}
+
+// A CSR is an immutable sparse matrix in compressed sparse row format.
+type CSR[N gnum.Number] struct {
+	rows, cols int
+	ptr        []int // Row i's elements are at ptr[i]:ptr[i+1].
+	idx        []int // Column of each element.
+	val        []N   // Value of each element.
+}
+
+// FromTriplets returns a rows x cols matrix containing the given elements.
+// Elements with the same coordinates are summed.
+// Elements whose value is zero are omitted.
+// Panics if an element's coordinates are out of range.
+func FromTriplets[N gnum.Number](rows, cols int, t []Triplet[N]) *CSR[N] {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("bad dimensions: %dx%d", rows, cols))
+	}
+	t = slices.Clone(t)
+	for _, x := range t {
+		if x.Row < 0 || x.Row >= rows || x.Col < 0 || x.Col >= cols {
+			panic(fmt.Sprintf("element (%d,%d) out of range for %dx%d matrix",
+				x.Row, x.Col, rows, cols))
+		}
+	}
+	slices.SortFunc(t, func(a, b Triplet[N]) int {
+		if c := cmp.Compare(a.Row, b.Row); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Col, b.Col)
+	})
+
+	m := &CSR[N]{rows: rows, cols: cols, ptr: make([]int, rows+1)}
+	for i := 0; i < len(t); {
+		x := t[i]
+		for i++; i < len(t) && t[i].Row == x.Row && t[i].Col == x.Col; i++ {
+			x.Val += t[i].Val
+		}
+		if x.Val == 0 {
+			continue
+		}
+		m.idx = append(m.idx, x.Col)
+		m.val = append(m.val, x.Val)
+		m.ptr[x.Row+1]++
+	}
+	for i := range rows {
+		m.ptr[i+1] += m.ptr[i]
+	}
+	return m
+}
+
+// FromDense returns a sparse matrix with the non-zero elements of a.
+// All rows in a must have the same length.
+func FromDense[S ~[]N, N gnum.Number](a []S) *CSR[N] {
+	cols := 0
+	if len(a) > 0 {
+		cols = len(a[0])
+	}
+	m := &CSR[N]{rows: len(a), cols: cols, ptr: make([]int, len(a)+1)}
+	for i, row := range a {
+		if len(row) != cols {
+			panic(fmt.Sprintf("row %d has length %d, want %d",
+				i, len(row), cols))
+		}
+		for j, v := range row {
+			if v != 0 {
+				m.idx = append(m.idx, j)
+				m.val = append(m.val, v)
+			}
+		}
+		m.ptr[i+1] = len(m.idx)
+	}
+	return m
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR[N]) Dims() (int, int) {
+	return m.rows, m.cols
+}
+
+// NNZ returns the number of non-zero elements in the matrix.
+func (m *CSR[N]) NNZ() int {
+	return len(m.val)
+}
+
+// At returns the element at row i and column j.
+// Runs in O(log(non-zeros in row i)).
+func (m *CSR[N]) At(i, j int) N {
+	m.checkRow(i)
+	if j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("column %d out of range [0,%d)", j, m.cols))
+	}
+	a, b := m.ptr[i], m.ptr[i+1]
+	k, ok := slices.BinarySearch(m.idx[a:b], j)
+	if !ok {
+		return 0
+	}
+	return m.val[a+k]
+}
+
+// Row iterates over the non-zero elements of row i,
+// yielding column index and value, ordered by column.
+func (m *CSR[N]) Row(i int) iter.Seq2[int, N] {
+	m.checkRow(i)
+	return func(yield func(int, N) bool) {
+		for k := m.ptr[i]; k < m.ptr[i+1]; k++ {
+			if !yield(m.idx[k], m.val[k]) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over the non-zero elements of the matrix,
+// ordered by row and then by column.
+func (m *CSR[N]) All() iter.Seq[Triplet[N]] {
+	return func(yield func(Triplet[N]) bool) {
+		for i := range m.rows {
+			for k := m.ptr[i]; k < m.ptr[i+1]; k++ {
+				if !yield(Triplet[N]{i, m.idx[k], m.val[k]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MulVec returns the product of m and the column vector x.
+// x must have a length equal to the number of columns.
+func (m *CSR[N]) MulVec(x []N) []N {
+	if len(x) != m.cols {
+		panic(fmt.Sprintf("vector length %d, want %d", len(x), m.cols))
+	}
+	y := make([]N, m.rows)
+	for i := range y {
+		var sum N
+		for k := m.ptr[i]; k < m.ptr[i+1]; k++ {
+			sum += m.val[k] * x[m.idx[k]]
+		}
+		y[i] = sum
+	}
+	return y
+}
+
+// Transpose returns the transpose of m.
+// Runs in O(rows + cols + non-zeros).
+func (m *CSR[N]) Transpose() *CSR[N] {
+	t := &CSR[N]{
+		rows: m.cols,
+		cols: m.rows,
+		ptr:  make([]int, m.cols+1),
+		idx:  make([]int, len(m.idx)),
+		val:  make([]N, len(m.val)),
+	}
+	for _, j := range m.idx {
+		t.ptr[j+1]++
+	}
+	for j := range m.cols {
+		t.ptr[j+1] += t.ptr[j]
+	}
+	next := slices.Clone(t.ptr[:m.cols])
+	for i := range m.rows {
+		for k := m.ptr[i]; k < m.ptr[i+1]; k++ {
+			j := m.idx[k]
+			t.idx[next[j]] = i
+			t.val[next[j]] = m.val[k]
+			next[j]++
+		}
+	}
+	return t
+}
+
+// Dense returns the matrix as a dense slice of rows.
+func (m *CSR[N]) Dense() [][]N {
+	a := make([][]N, m.rows)
+	for i := range a {
+		a[i] = make([]N, m.cols)
+		for j, v := range m.Row(i) {
+			a[i][j] = v
+		}
+	}
+	return a
+}
+
+// Panics if i is not a valid row index.
+func (m *CSR[N]) checkRow(i int) {
+	if i < 0 || i >= m.rows {
+		panic(fmt.Sprintf("row %d out of range [0,%d)", i, m.rows))
+	}
+}
@@ -0,0 +1,78 @@
+package sparse
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFromTriplets(t *testing.T) {
+	m := FromTriplets(3, 4, []Triplet[int]{
+		{2, 1, 5}, {0, 3, 1}, {0, 0, 2}, {2, 1, 1}, {1, 2, 0}, {0, 3, -1},
+	})
+	want := [][]int{
+		{2, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 6, 0, 0},
+	}
+	if got := m.Dense(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("FromTriplets(...)=%v, want %v", got, want)
+	}
+	if m.NNZ() != 2 {
+		t.Fatalf("NNZ()=%v, want 2", m.NNZ())
+	}
+	if got := m.At(2, 1); got != 6 {
+		t.Fatalf("At(2,1)=%v, want 6", got)
+	}
+	if got := m.At(1, 1); got != 0 {
+		t.Fatalf("At(1,1)=%v, want 0", got)
+	}
+}
+
+func TestMulVec(t *testing.T) {
+	m := FromDense([][]float64{
+		{1, 0, 2},
+		{0, 0, 0},
+		{0, 3, 4},
+	})
+	got := m.MulVec([]float64{1, 2, 3})
+	if want := []float64{7, 0, 18}; !slices.Equal(got, want) {
+		t.Fatalf("MulVec(...)=%v, want %v", got, want)
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	a := [][]int{
+		{1, 0, 2, 0},
+		{0, 0, 3, 0},
+		{4, 5, 0, 6},
+	}
+	want := [][]int{
+		{1, 0, 4},
+		{0, 0, 5},
+		{2, 3, 0},
+		{0, 0, 6},
+	}
+	m := FromDense(a).Transpose()
+	if r, c := m.Dims(); r != 4 || c != 3 {
+		t.Fatalf("Transpose().Dims()=%v,%v, want 4,3", r, c)
+	}
+	if got := m.Dense(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Transpose()=%v, want %v", got, want)
+	}
+	if got := m.Transpose().Dense(); !reflect.DeepEqual(got, a) {
+		t.Fatalf("Transpose().Transpose()=%v, want %v", got, a)
+	}
+}
+
+func TestRow(t *testing.T) {
+	m := FromDense([][]int{{0, 7, 0, 8}})
+	var cols, vals []int
+	for j, v := range m.Row(0) {
+		cols = append(cols, j)
+		vals = append(vals, v)
+	}
+	if !slices.Equal(cols, []int{1, 3}) || !slices.Equal(vals, []int{7, 8}) {
+		t.Fatalf("Row(0)=%v,%v, want [1 3],[7 8]", cols, vals)
+	}
+}
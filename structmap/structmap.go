@@ -0,0 +1,326 @@
+// Package structmap converts structs to maps and back.
+//
+// # Field names
+//
+// Exported fields are keyed by their names, or by the name part of their
+// structmap tag. Fields tagged "-" are skipped, and fields tagged with an
+// "omitempty" option are skipped in [ToMap] when they hold a zero value.
+// Untagged embedded structs have their fields promoted, as in
+// [encoding/json].
+//
+//	type Config struct {
+//	  Name    string `structmap:"name"`
+//	  Threads int    `structmap:"threads,omitempty"`
+//	  Secret  string `structmap:"-"`
+//	}
+//
+// # Nested structs
+//
+// Struct fields (other than [time.Time]) are converted to nested
+// map[string]any values, and back.
+//
+// # Type coercion
+//
+// [FromMap] converts values to their field's type when possible:
+// numbers convert between numeric types if they fit,
+// strings are parsed into numbers, bools, durations and RFC3339 times,
+// and []any and map[string]any are converted element-wise.
+package structmap
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tag key for field options.
+const tagKey = "structmap"
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// ToMap returns a map from t's field names to their values.
+// Panics if T is not a struct or a pointer to a struct.
+func ToMap[T any](t T) map[string]any {
+	v := reflect.ValueOf(t)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("expected struct, got %v", reflect.TypeFor[T]()))
+	}
+	return structToMap(v)
+}
+
+// FromMap sets t's fields from the values in m.
+// Keys with no matching field are ignored, and fields with no matching key
+// are left unchanged.
+// Panics if T is not a struct.
+func FromMap[T any](m map[string]any, t *T) error {
+	v := reflect.ValueOf(t).Elem()
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("expected struct, got %v", v.Type()))
+	}
+	return mapToStruct(m, v)
+}
+
+// A struct field with its parsed tag.
+type field struct {
+	index     []int
+	name      string
+	omitEmpty bool
+}
+
+// Returns the fields of a struct type, including promoted ones.
+func fields(t reflect.Type) []field {
+	var result []field
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get(tagKey)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, ff := range fields(f.Type) {
+				ff.index = append([]int{i}, ff.index...)
+				result = append(result, ff)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		result = append(result, field{[]int{i}, name,
+			strings.Contains(","+opts+",", ",omitempty,")})
+	}
+	return result
+}
+
+// Converts a struct value to a map.
+func structToMap(v reflect.Value) map[string]any {
+	m := map[string]any{}
+	for _, f := range fields(v.Type()) {
+		fv := v.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		m[f.name] = toMapValue(fv)
+	}
+	return m
+}
+
+// Returns the map representation of a field value.
+func toMapValue(v reflect.Value) any {
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != timeType:
+		return structToMap(v)
+	case v.Kind() == reflect.Pointer && !v.IsNil() &&
+		v.Elem().Kind() == reflect.Struct && v.Elem().Type() != timeType:
+		return structToMap(v.Elem())
+	}
+	return v.Interface()
+}
+
+// Sets a struct value's fields from a map.
+func mapToStruct(m map[string]any, v reflect.Value) error {
+	for _, f := range fields(v.Type()) {
+		x, ok := m[f.name]
+		if !ok {
+			continue
+		}
+		if err := set(v.FieldByIndex(f.index), x); err != nil {
+			return fmt.Errorf("field %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// Sets dst to x, converting it if needed.
+func set(dst reflect.Value, x any) error {
+	if x == nil {
+		dst.SetZero()
+		return nil
+	}
+	src := reflect.ValueOf(x)
+	t := dst.Type()
+
+	switch {
+	case src.Type().AssignableTo(t):
+		dst.Set(src)
+		return nil
+	case t.Kind() == reflect.Pointer:
+		p := reflect.New(t.Elem())
+		if err := set(p.Elem(), x); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	case t.Kind() == reflect.Struct && t != timeType:
+		m, ok := x.(map[string]any)
+		if !ok {
+			break
+		}
+		return mapToStruct(m, dst)
+	case t == timeType:
+		s, ok := x.(string)
+		if !ok {
+			break
+		}
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(tm))
+		return nil
+	case t == reflect.TypeFor[time.Duration]() && src.Kind() == reflect.String:
+		d, err := time.ParseDuration(src.String())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	case t.Kind() == reflect.Slice && src.Kind() == reflect.Slice:
+		s := reflect.MakeSlice(t, src.Len(), src.Len())
+		for i := range src.Len() {
+			if err := set(s.Index(i), src.Index(i).Interface()); err != nil {
+				return fmt.Errorf("element #%d: %w", i, err)
+			}
+		}
+		dst.Set(s)
+		return nil
+	case t.Kind() == reflect.Map && src.Kind() == reflect.Map &&
+		t.Key() == src.Type().Key():
+		m := reflect.MakeMapWithSize(t, src.Len())
+		for it := src.MapRange(); it.Next(); {
+			e := reflect.New(t.Elem()).Elem()
+			if err := set(e, it.Value().Interface()); err != nil {
+				return fmt.Errorf("key %v: %w", it.Key(), err)
+			}
+			m.SetMapIndex(it.Key(), e)
+		}
+		dst.Set(m)
+		return nil
+	case src.Kind() == reflect.String:
+		return setFromString(dst, src.String())
+	default:
+		if ok, err := setNumber(dst, src); ok {
+			return err
+		}
+	}
+	return fmt.Errorf("cannot convert %v to %v", src.Type(), t)
+}
+
+// Parses s into a basic-kind value.
+func setFromString(dst reflect.Value, s string) error {
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		i, err := strconv.ParseUint(s, 0, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot convert string to %v", dst.Type())
+	}
+	return nil
+}
+
+// Converts a numeric value to dst's numeric type.
+// Returns false if either value is not numeric.
+func setNumber(dst, src reflect.Value) (bool, error) {
+	var f float64 // Value as float, for range checks.
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(src.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		f = float64(src.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = src.Float()
+	default:
+		return false, nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch src.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return true, fmt.Errorf("%v does not fit in %v", f, dst.Type())
+			}
+			i = int64(f)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64:
+			if src.Uint() > math.MaxInt64 {
+				return true, fmt.Errorf("%v does not fit in %v",
+					src.Uint(), dst.Type())
+			}
+			i = int64(src.Uint())
+		default:
+			i = src.Int()
+		}
+		if dst.OverflowInt(i) {
+			return true, fmt.Errorf("%v does not fit in %v", i, dst.Type())
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		var u uint64
+		switch src.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return true, fmt.Errorf("%v does not fit in %v", f, dst.Type())
+			}
+			u = uint64(f)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			if src.Int() < 0 {
+				return true, fmt.Errorf("%v does not fit in %v",
+					src.Int(), dst.Type())
+			}
+			u = uint64(src.Int())
+		default:
+			u = src.Uint()
+		}
+		if dst.OverflowUint(u) {
+			return true, fmt.Errorf("%v does not fit in %v", u, dst.Type())
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if dst.OverflowFloat(f) {
+			return true, fmt.Errorf("%v does not fit in %v", f, dst.Type())
+		}
+		dst.SetFloat(f)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
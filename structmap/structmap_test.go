@@ -0,0 +1,115 @@
+package structmap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	X int
+	Y string `structmap:"y"`
+}
+
+type base struct {
+	ID int `structmap:"id"`
+}
+
+type outer struct {
+	base
+	Name    string        `structmap:"name"`
+	Count   int           `structmap:"count,omitempty"`
+	Ratio   float32       `structmap:"ratio"`
+	Secret  string        `structmap:"-"`
+	In      inner         `structmap:"in"`
+	InP     *inner        `structmap:"inp"`
+	Tags    []string      `structmap:"tags"`
+	At      time.Time     `structmap:"at"`
+	Took    time.Duration `structmap:"took"`
+	private int
+}
+
+func TestToMap(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	input := outer{base{7}, "a", 0, 0.5, "s", inner{1, "b"}, &inner{2, "c"},
+		[]string{"x"}, at, time.Second, 3}
+	want := map[string]any{
+		"id":    7,
+		"name":  "a",
+		"ratio": float32(0.5),
+		"in":    map[string]any{"X": 1, "y": "b"},
+		"inp":   map[string]any{"X": 2, "y": "c"},
+		"tags":  []string{"x"},
+		"at":    at,
+		"took":  time.Second,
+	}
+	got := ToMap(input)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ToMap(%v)=%v, want %v", input, got, want)
+	}
+	if got := ToMap(&input); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ToMap(&%v)=%v, want %v", input, got, want)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]any{
+		"id":     "12",
+		"name":   "a",
+		"count":  3.0,
+		"ratio":  2,
+		"Secret": "s",
+		"in":     map[string]any{"X": int64(1), "y": "b"},
+		"inp":    map[string]any{"X": "2"},
+		"tags":   []any{"x", "y"},
+		"at":     "2024-01-02T03:04:05Z",
+		"took":   "1m",
+		"other":  true,
+	}
+	want := outer{base{12}, "a", 3, 2, "", inner{1, "b"}, &inner{2, ""},
+		[]string{"x", "y"}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Minute, 0}
+	var got outer
+	if err := FromMap(m, &got); err != nil {
+		t.Fatalf("FromMap(%v) failed: %v", m, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FromMap(%v)=%+v, want %+v", m, got, want)
+	}
+}
+
+func TestFromMap_roundTrip(t *testing.T) {
+	want := outer{base{1}, "a", 2, 0.25, "", inner{3, "b"}, nil,
+		nil, time.Time{}, 0, 0}
+	var got outer
+	if err := FromMap(ToMap(want), &got); err != nil {
+		t.Fatalf("FromMap(ToMap(%v)) failed: %v", want, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FromMap(ToMap(%+v))=%+v", want, got)
+	}
+}
+
+func TestFromMap_bad(t *testing.T) {
+	tests := []map[string]any{
+		{"count": 1.5},
+		{"count": "a"},
+		{"in": map[string]any{"X": uint64(1 << 63)}},
+		{"name": 5},
+		{"tags": []any{1}},
+		{"at": "yesterday"},
+		{"in": 5},
+	}
+	for _, m := range tests {
+		var got outer
+		if err := FromMap(m, &got); err == nil {
+			t.Errorf("FromMap(%v)=%+v, want error", m, got)
+		}
+	}
+
+	type small struct{ A int8 }
+	var s small
+	if err := FromMap(map[string]any{"A": 300}, &s); err == nil {
+		t.Errorf("FromMap(300 into int8)=%v, want error", s)
+	}
+}
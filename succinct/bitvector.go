@@ -0,0 +1,199 @@
+// Package succinct provides compact data structures that support fast
+// queries without decompression.
+package succinct
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+
+	"github.com/fluhus/gostuff/bitset"
+)
+
+const (
+	wordsPerSuper = 8                  // Words in a superblock.
+	bitsPerSuper  = 64 * wordsPerSuper // Bits in a superblock.
+	selectSample  = 512                // Ones between select samples.
+
+	// Superblocks spanned by a sample's ones, above which their positions
+	// are stored explicitly.
+	sparseSupers = 512
+)
+
+// A BitVector is an immutable bit array with constant-time rank and select
+// queries. Its rank index takes 37.5% extra space over the bits themselves,
+// and its select index takes up to about 12.5% more.
+type BitVector struct {
+	w      []uint64
+	n      int           // Number of bits.
+	super  []int         // Number of ones before each superblock.
+	block  []uint16      // Ones before each word, within its superblock.
+	sample []int         // Superblock of every selectSample'th one.
+	sparse map[int][]int // Positions of the ones of sparse samples.
+	ones   int
+}
+
+// FromWords returns a bit vector of n bits, where bit i is bit i%64 of
+// words[i/64]. Bits at n and above are ignored. words is not modified.
+func FromWords(words []uint64, n int) *BitVector {
+	if n < 0 || n > len(words)*64 {
+		panic(fmt.Sprintf("bad n: %d for %d words", n, len(words)))
+	}
+	w := make([]uint64, (n+63)/64)
+	copy(w, words)
+	if n%64 != 0 {
+		w[len(w)-1] &= 1<<(n%64) - 1
+	}
+	return build(w, n)
+}
+
+// FromBitset returns a bit vector with the first n bits of b.
+func FromBitset(b *bitset.Bitset, n int) *BitVector {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	w := make([]uint64, (n+63)/64)
+	for i := range b.Ones() {
+		if i >= n {
+			break
+		}
+		w[i/64] |= 1 << (i % 64)
+	}
+	return build(w, n)
+}
+
+// Builds the rank and select index over the given words.
+func build(w []uint64, n int) *BitVector {
+	v := &BitVector{
+		w:     w,
+		n:     n,
+		super: make([]int, 0, len(w)/wordsPerSuper+1),
+		block: make([]uint16, len(w)),
+	}
+	ones := 0
+	for i, x := range w {
+		if i%wordsPerSuper == 0 {
+			v.super = append(v.super, ones)
+		}
+		v.block[i] = uint16(ones - v.super[len(v.super)-1])
+		c := bits.OnesCount64(x)
+		// Sample the superblock of every selectSample'th one.
+		for len(v.sample)*selectSample < ones+c {
+			v.sample = append(v.sample, len(v.super)-1)
+		}
+		ones += c
+	}
+	v.ones = ones
+
+	// Store the positions of ones of samples that span many superblocks,
+	// so that Select1 searches a bounded range.
+	for i := range v.sample {
+		lo, hi := v.sampleRange(i)
+		if hi-lo <= sparseSupers {
+			continue
+		}
+		if v.sparse == nil {
+			v.sparse = map[int][]int{}
+		}
+		v.sparse[i] = v.positions(lo, i*selectSample,
+			min(selectSample, ones-i*selectSample))
+	}
+	return v
+}
+
+// Returns the range of superblocks that hold the ones of sample i.
+func (v *BitVector) sampleRange(i int) (int, int) {
+	if i+1 < len(v.sample) {
+		return v.sample[i], v.sample[i+1] + 1
+	}
+	return v.sample[i], len(v.super)
+}
+
+// Returns the positions of n ones starting from the k'th one, which is in
+// superblock s.
+func (v *BitVector) positions(s, k, n int) []int {
+	result := make([]int, 0, n)
+	skip := k - v.super[s]
+	for wi := s * wordsPerSuper; len(result) < n; wi++ {
+		for x := v.w[wi]; x != 0 && len(result) < n; x &= x - 1 {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			result = append(result, wi*64+bits.TrailingZeros64(x))
+		}
+	}
+	return result
+}
+
+// Len returns the number of bits in the vector.
+func (v *BitVector) Len() int {
+	return v.n
+}
+
+// Ones returns the number of bits that are 1.
+func (v *BitVector) Ones() int {
+	return v.ones
+}
+
+// Test returns whether bit i is 1.
+func (v *BitVector) Test(i int) bool {
+	v.checkIndex(i, v.n-1)
+	return v.w[i/64]&(1<<(i%64)) != 0
+}
+
+// Rank1 returns the number of ones in bits [0,i). i can be at most Len.
+func (v *BitVector) Rank1(i int) int {
+	v.checkIndex(i, v.n)
+	if i == v.n {
+		return v.ones
+	}
+	wi := i / 64
+	return v.super[wi/wordsPerSuper] + int(v.block[wi]) +
+		bits.OnesCount64(v.w[wi]&(1<<(i%64)-1))
+}
+
+// Rank0 returns the number of zeros in bits [0,i). i can be at most Len.
+func (v *BitVector) Rank0(i int) int {
+	return i - v.Rank1(i)
+}
+
+// Select1 returns the position of the k'th one, counting from 0.
+// Returns -1 if there are at most k ones.
+func (v *BitVector) Select1(k int) int {
+	if k < 0 || k >= v.ones {
+		return -1
+	}
+	if pos, ok := v.sparse[k/selectSample]; ok {
+		return pos[k%selectSample]
+	}
+	// Find the superblock, by binary search between consecutive samples.
+	// The range is at most sparseSupers long.
+	lo, hi := v.sampleRange(k / selectSample)
+	i, _ := slices.BinarySearch(v.super[lo:hi], k+1)
+	s := lo + i - 1
+	// Find the word.
+	k -= v.super[s]
+	wi := s * wordsPerSuper
+	for wi+1 < len(v.w) && wi+1 < (s+1)*wordsPerSuper &&
+		int(v.block[wi+1]) <= k {
+		wi++
+	}
+	k -= int(v.block[wi])
+	return wi*64 + selectInWord(v.w[wi], k)
+}
+
+// Returns the position of the k'th one in x, counting from 0.
+func selectInWord(x uint64, k int) int {
+	for range k {
+		x &= x - 1 // Clear lowest one.
+	}
+	return bits.TrailingZeros64(x)
+}
+
+// Panics if i is not in [0,maxi].
+func (v *BitVector) checkIndex(i, maxi int) {
+	if i < 0 || i > maxi {
+		panic(fmt.Sprintf("index out of range: %d with length %d", i, v.n))
+	}
+}
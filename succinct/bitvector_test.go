@@ -0,0 +1,78 @@
+package succinct
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fluhus/gostuff/bitset"
+)
+
+func TestBitVector(t *testing.T) {
+	for _, n := range []int{0, 1, 63, 64, 65, 511, 512, 513, 5000, 100000} {
+		for _, p := range []float64{0, 0.01, 0.5, 1} {
+			words := make([]uint64, (n+63)/64+1)
+			bs := &bitset.Bitset{}
+			var want []bool
+			for i := range n {
+				bit := rand.Float64() < p
+				want = append(want, bit)
+				if bit {
+					words[i/64] |= 1 << (i % 64)
+					bs.Set(i)
+				}
+			}
+			words[len(words)-1] = ^uint64(0) // Should be ignored.
+			checkBitVector(t, FromWords(words, n), want)
+			checkBitVector(t, FromBitset(bs, n), want)
+		}
+	}
+}
+
+func checkBitVector(t *testing.T, v *BitVector, want []bool) {
+	t.Helper()
+	if v.Len() != len(want) {
+		t.Fatalf("Len()=%v, want %v", v.Len(), len(want))
+	}
+	rank := 0
+	for i, bit := range want {
+		if got := v.Rank1(i); got != rank {
+			t.Fatalf("Rank1(%v)=%v, want %v", i, got, rank)
+		}
+		if got := v.Rank0(i); got != i-rank {
+			t.Fatalf("Rank0(%v)=%v, want %v", i, got, i-rank)
+		}
+		if got := v.Test(i); got != bit {
+			t.Fatalf("Test(%v)=%v, want %v", i, got, bit)
+		}
+		if bit {
+			if got := v.Select1(rank); got != i {
+				t.Fatalf("Select1(%v)=%v, want %v", rank, got, i)
+			}
+			rank++
+		}
+	}
+	if got := v.Rank1(len(want)); got != rank {
+		t.Fatalf("Rank1(%v)=%v, want %v", len(want), got, rank)
+	}
+	if v.Ones() != rank {
+		t.Fatalf("Ones()=%v, want %v", v.Ones(), rank)
+	}
+	if got := v.Select1(rank); got != -1 {
+		t.Fatalf("Select1(%v)=%v, want -1", rank, got)
+	}
+}
+
+func TestBitVector_sparse(t *testing.T) {
+	const n = 3000000
+	want := make([]bool, n)
+	words := make([]uint64, (n+63)/64)
+	for i := 0; i < n; i += 999 {
+		want[i] = true
+		words[i/64] |= 1 << (i % 64)
+	}
+	v := FromWords(words, n)
+	if len(v.sparse) == 0 {
+		t.Fatalf("len(sparse)=0, want positive")
+	}
+	checkBitVector(t, v, want)
+}
@@ -0,0 +1,184 @@
+package syncx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A Coalesced function coalesces bursts of calls into fewer runs of an
+// underlying function. Created by [Debounce] and [Throttle].
+// Safe for concurrent use. Runs of the underlying function never overlap.
+type Coalesced[T any] struct {
+	fn       func(T)
+	d        time.Duration
+	throttle bool
+	leading  bool
+	trailing bool
+
+	mu      sync.Mutex
+	run     sync.Mutex  // Serializes runs of fn.
+	timer   *time.Timer // Nil until first use.
+	due     time.Time   // When a debounce timer should fire.
+	active  bool        // Inside a debounce or throttle window.
+	pending bool        // A trailing run is due.
+	last    T           // Argument of the trailing run.
+	stopped bool
+	stopCtx func() bool
+}
+
+// An Option modifies the behavior of [Debounce] and [Throttle].
+type Option func(*options)
+
+type options struct {
+	leading, trailing bool
+}
+
+// Leading sets whether the first call of a burst runs immediately.
+func Leading(b bool) Option {
+	return func(o *options) { o.leading = b }
+}
+
+// Trailing sets whether the last call of a burst runs when the burst ends.
+func Trailing(b bool) Option {
+	return func(o *options) { o.trailing = b }
+}
+
+// Debounce returns a [Coalesced] that runs fn only after no calls were
+// made for duration d, with the argument of the last call.
+// By default runs on the trailing edge only.
+//
+// When ctx is done, a pending run is flushed and further calls are
+// ignored. If ctx is already done, all calls are ignored.
+func Debounce[T any](ctx context.Context, fn func(T), d time.Duration,
+	opts ...Option) *Coalesced[T] {
+	return newCoalesced(ctx, fn, d, false, options{false, true}, opts)
+}
+
+// Throttle returns a [Coalesced] that runs fn at most once per interval d.
+// By default runs on both edges: the first call of a burst runs
+// immediately, and the last call in each interval runs when it ends.
+//
+// When ctx is done, a pending run is flushed and further calls are
+// ignored. If ctx is already done, all calls are ignored.
+func Throttle[T any](ctx context.Context, fn func(T), d time.Duration,
+	opts ...Option) *Coalesced[T] {
+	return newCoalesced(ctx, fn, d, true, options{true, true}, opts)
+}
+
+func newCoalesced[T any](ctx context.Context, fn func(T), d time.Duration,
+	throttle bool, o options, opts []Option) *Coalesced[T] {
+	if d <= 0 {
+		panic(fmt.Sprintf("bad duration: %v", d))
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := &Coalesced[T]{fn: fn, d: d, throttle: throttle,
+		leading: o.leading, trailing: o.trailing}
+	if ctx.Err() != nil {
+		c.stopped = true
+		c.stopCtx = func() bool { return false }
+		return c
+	}
+	c.stopCtx = context.AfterFunc(ctx, func() {
+		c.Flush()
+		c.Stop()
+	})
+	return c
+}
+
+// Call requests a run of the underlying function with argument x.
+func (c *Coalesced[T]) Call(x T) {
+	c.mu.Lock()
+	if c.stopped {
+		c.mu.Unlock()
+		return
+	}
+	if !c.active {
+		c.active = true
+		c.due = time.Now().Add(c.d)
+		if c.timer == nil {
+			c.timer = time.AfterFunc(c.d, c.fire)
+		} else {
+			c.timer.Reset(c.d)
+		}
+		if c.leading {
+			c.mu.Unlock()
+			c.exec(x)
+			return
+		}
+	} else if !c.throttle {
+		c.due = time.Now().Add(c.d)
+		c.timer.Reset(c.d)
+	}
+	if c.trailing {
+		c.pending, c.last = true, x
+	}
+	c.mu.Unlock()
+}
+
+// Called when a window ends.
+func (c *Coalesced[T]) fire() {
+	c.mu.Lock()
+	if c.stopped || time.Now().Before(c.due) {
+		// Stopped, or the timer was re-armed by a call.
+		c.mu.Unlock()
+		return
+	}
+	if !c.pending {
+		c.active = false
+		c.mu.Unlock()
+		return
+	}
+	x := c.take()
+	if c.throttle {
+		// The trailing run starts a new window.
+		c.due = time.Now().Add(c.d)
+		c.timer.Reset(c.d)
+	} else {
+		c.active = false
+	}
+	c.mu.Unlock()
+	c.exec(x)
+}
+
+// Flush runs a pending trailing call immediately, if there is one.
+func (c *Coalesced[T]) Flush() {
+	c.mu.Lock()
+	if c.stopped || !c.pending {
+		c.mu.Unlock()
+		return
+	}
+	x := c.take()
+	c.mu.Unlock()
+	c.exec(x)
+}
+
+// Stop drops any pending call and ignores further calls.
+func (c *Coalesced[T]) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	c.take()
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.stopCtx()
+}
+
+// Returns and clears the pending argument. Must be called with the lock held.
+func (c *Coalesced[T]) take() T {
+	x := c.last
+	var zero T
+	c.last, c.pending = zero, false
+	return x
+}
+
+// Runs fn, one run at a time.
+func (c *Coalesced[T]) exec(x T) {
+	c.run.Lock()
+	defer c.run.Unlock()
+	c.fn(x)
+}
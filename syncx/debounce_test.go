@@ -0,0 +1,124 @@
+package syncx
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Records the arguments of calls.
+type recorder struct {
+	mu    sync.Mutex
+	calls []int
+}
+
+func (r *recorder) fn(x int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, x)
+}
+
+func (r *recorder) get() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+func TestDebounce(t *testing.T) {
+	r := &recorder{}
+	d := Debounce(context.Background(), r.fn, 50*time.Millisecond)
+	defer d.Stop()
+	for i := range 5 {
+		d.Call(i)
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := r.get(); len(got) != 0 {
+		t.Fatalf("calls during burst=%v, want none", got)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if got, want := r.get(), []int{4}; !slices.Equal(got, want) {
+		t.Fatalf("calls after burst=%v, want %v", got, want)
+	}
+}
+
+func TestDebounce_leading(t *testing.T) {
+	r := &recorder{}
+	d := Debounce(context.Background(), r.fn, 50*time.Millisecond,
+		Leading(true), Trailing(false))
+	defer d.Stop()
+	for i := range 5 {
+		d.Call(i)
+	}
+	time.Sleep(150 * time.Millisecond)
+	d.Call(5)
+	if got, want := r.get(), []int{0, 5}; !slices.Equal(got, want) {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	r := &recorder{}
+	th := Throttle(context.Background(), r.fn, 50*time.Millisecond)
+	defer th.Stop()
+	for i := range 5 {
+		th.Call(i)
+	}
+	if got, want := r.get(), []int{0}; !slices.Equal(got, want) {
+		t.Fatalf("calls after burst=%v, want %v", got, want)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if got, want := r.get(), []int{0, 4}; !slices.Equal(got, want) {
+		t.Fatalf("calls after interval=%v, want %v", got, want)
+	}
+}
+
+func TestCoalesced_flushAndStop(t *testing.T) {
+	r := &recorder{}
+	d := Debounce(context.Background(), r.fn, time.Hour)
+	d.Call(1)
+	d.Flush()
+	d.Call(2)
+	d.Stop()
+	d.Call(3)
+	d.Flush()
+	if got, want := r.get(), []int{1}; !slices.Equal(got, want) {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+}
+
+func TestCoalesced_context(t *testing.T) {
+	r := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := Debounce(ctx, r.fn, time.Hour)
+	d.Call(1)
+	d.Call(2)
+	cancel()
+	for range 100 {
+		d.mu.Lock()
+		stopped := d.stopped
+		d.mu.Unlock()
+		if stopped {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	d.Call(3)
+	d.Flush()
+	if got, want := r.get(), []int{2}; !slices.Equal(got, want) {
+		t.Fatalf("calls=%v, want %v", got, want)
+	}
+}
+
+func TestCoalesced_doneContext(t *testing.T) {
+	r := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	th := Throttle(ctx, r.fn, time.Hour)
+	th.Call(1)
+	th.Flush()
+	if got := r.get(); len(got) != 0 {
+		t.Fatalf("calls=%v, want none", got)
+	}
+}
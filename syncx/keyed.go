@@ -0,0 +1,100 @@
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+// A KeyedMutex holds a separate lock for each key.
+// Entries of unused keys are removed, so memory is proportional to the
+// number of keys that are locked or waited on.
+// The zero value is ready for use.
+type KeyedMutex[K comparable] struct {
+	mu sync.Mutex
+	m  map[K]*keyLock
+}
+
+// The lock of a single key.
+type keyLock struct {
+	ch   chan struct{} // Holds a value while locked.
+	refs int           // Number of holders and waiters.
+}
+
+// Lock locks key k, blocking until it is available.
+func (m *KeyedMutex[K]) Lock(k K) {
+	m.acquire(k).ch <- struct{}{}
+}
+
+// LockContext locks key k, blocking until it is available or ctx is done.
+// Returns ctx's error on failure, in which case k is not locked.
+func (m *KeyedMutex[K]) LockContext(ctx context.Context, k K) error {
+	l := m.acquire(k)
+	select {
+	case l.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		m.release(k, l)
+		return ctx.Err()
+	}
+}
+
+// TryLock locks key k if it is available, and reports whether it did.
+func (m *KeyedMutex[K]) TryLock(k K) bool {
+	l := m.acquire(k)
+	select {
+	case l.ch <- struct{}{}:
+		return true
+	default:
+		m.release(k, l)
+		return false
+	}
+}
+
+// Unlock unlocks key k. Panics if k is not locked.
+func (m *KeyedMutex[K]) Unlock(k K) {
+	m.mu.Lock()
+	l := m.m[k]
+	m.mu.Unlock()
+	if l == nil {
+		panic("unlock of unlocked key")
+	}
+	select {
+	case <-l.ch:
+	default:
+		panic("unlock of unlocked key")
+	}
+	m.release(k, l)
+}
+
+// Len returns the number of keys that are locked or waited on.
+func (m *KeyedMutex[K]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.m)
+}
+
+// Returns the lock of k, creating it if needed, and adds a reference to it.
+func (m *KeyedMutex[K]) acquire(k K) *keyLock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.m == nil {
+		m.m = map[K]*keyLock{}
+	}
+	l := m.m[k]
+	if l == nil {
+		l = &keyLock{ch: make(chan struct{}, 1)}
+		m.m[k] = l
+	}
+	l.refs++
+	return l
+}
+
+// Removes a reference from k's lock, and removes the lock if unused.
+func (m *KeyedMutex[K]) release(k K, l *keyLock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(m.m, k)
+	}
+}
@@ -0,0 +1,97 @@
+package syncx
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// A Pool is a typed pool of reusable objects, that counts its usage.
+// By default it is backed by a [sync.Pool], so idle objects may be
+// freed by the garbage collector. With a max-idle cap, idle objects are
+// kept in a bounded free list instead.
+//
+// As with sync.Pool, T should typically be a pointer type, to avoid
+// allocations when storing objects.
+type Pool[T any] struct {
+	newFn func() T
+	reset func(T)
+	pool  sync.Pool
+	idle  chan T // Nil if there is no cap.
+
+	gets, puts, misses, drops atomic.Int64
+}
+
+// PoolStats holds the usage counts of a pool.
+type PoolStats struct {
+	Gets   int64 // Calls to Get
+	Puts   int64 // Calls to Put
+	Misses int64 // Gets that created a new object
+	Drops  int64 // Puts that discarded the object because the pool was full
+}
+
+// NewPool returns a pool that creates new objects with newFn.
+func NewPool[T any](newFn func() T) *Pool[T] {
+	return &Pool[T]{newFn: newFn}
+}
+
+// WithReset sets a function that resets objects when they are returned
+// to the pool, and returns p. Must be called before the pool is used.
+func (p *Pool[T]) WithReset(reset func(T)) *Pool[T] {
+	p.reset = reset
+	return p
+}
+
+// WithMaxIdle caps the number of idle objects kept by the pool at n,
+// and returns p. Must be called before the pool is used.
+func (p *Pool[T]) WithMaxIdle(n int) *Pool[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	p.idle = make(chan T, n)
+	return p
+}
+
+// Get returns an idle object from the pool, or a new one if there is none.
+func (p *Pool[T]) Get() T {
+	p.gets.Add(1)
+	if p.idle != nil {
+		select {
+		case x := <-p.idle:
+			return x
+		default:
+		}
+	} else if x := p.pool.Get(); x != nil {
+		return x.(T)
+	}
+	p.misses.Add(1)
+	return p.newFn()
+}
+
+// Put resets x and returns it to the pool.
+// x must not be used after calling Put.
+func (p *Pool[T]) Put(x T) {
+	p.puts.Add(1)
+	if p.reset != nil {
+		p.reset(x)
+	}
+	if p.idle == nil {
+		p.pool.Put(x)
+		return
+	}
+	select {
+	case p.idle <- x:
+	default:
+		p.drops.Add(1)
+	}
+}
+
+// Stats returns the pool's usage counts.
+func (p *Pool[T]) Stats() PoolStats {
+	return PoolStats{
+		Gets:   p.gets.Load(),
+		Puts:   p.puts.Load(),
+		Misses: p.misses.Load(),
+		Drops:  p.drops.Load(),
+	}
+}
@@ -0,0 +1,52 @@
+package syncx
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestPool_maxIdle(t *testing.T) {
+	p := NewPool(func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}).WithReset((*bytes.Buffer).Reset).WithMaxIdle(2)
+
+	a, b, c := p.Get(), p.Get(), p.Get()
+	a.WriteString("hello")
+	p.Put(a)
+	p.Put(b)
+	p.Put(c)
+	if got := p.Get(); got != a || got.Len() != 0 {
+		t.Errorf("Get()=%p (len %d), want %p (len 0)", got, got.Len(), a)
+	}
+	p.Get()
+	p.Get()
+
+	want := PoolStats{Gets: 6, Puts: 3, Misses: 4, Drops: 1}
+	if got := p.Stats(); got != want {
+		t.Errorf("Stats()=%+v, want %+v", got, want)
+	}
+}
+
+func TestPool_concurrent(t *testing.T) {
+	p := NewPool(func() *[]int {
+		return new([]int)
+	})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				x := p.Get()
+				*x = append((*x)[:0], 1)
+				p.Put(x)
+			}
+		}()
+	}
+	wg.Wait()
+	s := p.Stats()
+	if s.Gets != 1000 || s.Puts != 1000 || s.Misses > s.Gets || s.Drops != 0 {
+		t.Errorf("Stats()=%+v, want 1000 gets and puts", s)
+	}
+}
@@ -0,0 +1,120 @@
+// Package syncx provides additional synchronization primitives.
+package syncx
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// A Semaphore bounds access to a resource by total weight.
+// Waiters are served in FIFO order, so a large request is not starved by
+// smaller ones.
+type Semaphore struct {
+	size    int64
+	cur     int64
+	mu      sync.Mutex
+	waiters list.List // Of *waiter.
+}
+
+// A waiter for a semaphore.
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewSemaphore returns a semaphore with the given total weight.
+func NewSemaphore(n int64) *Semaphore {
+	if n < 0 {
+		panic(fmt.Sprintf("bad n: %d", n))
+	}
+	return &Semaphore{size: n}
+}
+
+// Acquire acquires weight n, blocking until it is available or ctx is
+// done. Returns ctx's error on failure, in which case nothing is acquired.
+// Panics if n is greater than the semaphore's size.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	s.checkN(n)
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	w := &waiter{n, make(chan struct{})}
+	e := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// Acquired after ctx was done; give it back.
+			s.cur -= n
+			s.notify()
+		default:
+			front := s.waiters.Front() == e
+			s.waiters.Remove(e)
+			if front {
+				s.notify()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires weight n without blocking.
+// Returns false if it is not available, in which case nothing is acquired.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	s.checkN(n)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+// Release releases weight n.
+// Panics if more than the acquired weight is released.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 0 || n > s.cur {
+		panic(fmt.Sprintf("released %d with %d acquired", n, s.cur))
+	}
+	s.cur -= n
+	s.notify()
+}
+
+// Wakes waiters from the front of the queue while there is room.
+// Must be called with the lock held.
+func (s *Semaphore) notify() {
+	for {
+		e := s.waiters.Front()
+		if e == nil {
+			return
+		}
+		w := e.Value.(*waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(e)
+		close(w.ready)
+	}
+}
+
+// Panics if n is not a valid weight.
+func (s *Semaphore) checkN(n int64) {
+	if n < 0 || n > s.size {
+		panic(fmt.Sprintf("bad n: %d, want 0-%d", n, s.size))
+	}
+}
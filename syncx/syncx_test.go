@@ -0,0 +1,155 @@
+package syncx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(3)
+	var cur, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := int64(i%3 + 1)
+			if err := s.Acquire(context.Background(), n); err != nil {
+				t.Errorf("Acquire(%d) failed: %v", n, err)
+				return
+			}
+			c := cur.Add(n)
+			for {
+				p := peak.Load()
+				if c <= p || peak.CompareAndSwap(p, c) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			cur.Add(-n)
+			s.Release(n)
+		}()
+	}
+	wg.Wait()
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak weight=%d, want at most 3", p)
+	}
+}
+
+func TestSemaphore_try(t *testing.T) {
+	s := NewSemaphore(2)
+	if !s.TryAcquire(2) {
+		t.Fatalf("TryAcquire(2)=false, want true")
+	}
+	if s.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1)=true, want false")
+	}
+	s.Release(1)
+	if !s.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1)=false, want true")
+	}
+}
+
+func TestSemaphore_cancel(t *testing.T) {
+	s := NewSemaphore(2)
+	s.TryAcquire(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx, 2); err == nil {
+		t.Fatalf("Acquire(2)=nil, want error")
+	}
+	// A canceled large waiter should not block smaller ones.
+	if !s.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1)=false, want true")
+	}
+}
+
+func TestSemaphore_fifo(t *testing.T) {
+	s := NewSemaphore(2)
+	s.TryAcquire(1)
+	done := make(chan struct{})
+	go func() {
+		s.Acquire(context.Background(), 2)
+		close(done)
+	}()
+	for {
+		s.mu.Lock()
+		n := s.waiters.Len()
+		s.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s.TryAcquire(1) {
+		t.Fatalf("TryAcquire(1) with a waiter=true, want false")
+	}
+	s.Release(1)
+	<-done
+}
+
+func TestKeyedMutex(t *testing.T) {
+	var m KeyedMutex[string]
+	counts := map[string]*int{"a": new(int), "b": new(int), "c": new(int)}
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k := []string{"a", "b", "c"}[i%3]
+			m.Lock(k)
+			c := *counts[k] // Unsynchronized access is guarded per key.
+			time.Sleep(time.Microsecond)
+			*counts[k] = c + 1
+			m.Unlock(k)
+		}()
+	}
+	wg.Wait()
+	want := map[string]int{"a": 34, "b": 33, "c": 33}
+	for k, c := range counts {
+		if *c != want[k] {
+			t.Errorf("count[%q]=%d, want %d", k, *c, want[k])
+		}
+	}
+	if m.Len() != 0 {
+		t.Errorf("Len()=%d, want 0", m.Len())
+	}
+}
+
+func TestKeyedMutex_try(t *testing.T) {
+	var m KeyedMutex[int]
+	if !m.TryLock(1) {
+		t.Fatalf("TryLock(1)=false, want true")
+	}
+	if m.TryLock(1) {
+		t.Fatalf("TryLock(1)=true, want false")
+	}
+	if !m.TryLock(2) {
+		t.Fatalf("TryLock(2)=false, want true")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.LockContext(ctx, 1); err == nil {
+		t.Fatalf("LockContext(1)=nil, want error")
+	}
+	m.Unlock(1)
+	m.Unlock(2)
+	if m.Len() != 0 {
+		t.Errorf("Len()=%d, want 0", m.Len())
+	}
+}
+
+func TestTryDo(t *testing.T) {
+	var mu sync.Mutex
+	ran := false
+	if !TryDo(&mu, func() { ran = true }) || !ran {
+		t.Fatalf("TryDo(unlocked)=false, want true")
+	}
+	mu.Lock()
+	if TryDo(&mu, func() { t.Errorf("TryDo(locked) ran f") }) {
+		t.Fatalf("TryDo(locked)=true, want false")
+	}
+}
@@ -0,0 +1,19 @@
+package syncx
+
+// A TryLocker is a lock that can be acquired without blocking,
+// like [sync.Mutex].
+type TryLocker interface {
+	TryLock() bool
+	Unlock()
+}
+
+// TryDo runs f while holding l, if l can be locked without blocking.
+// Reports whether f was run. l is unlocked even if f panics.
+func TryDo(l TryLocker, f func()) bool {
+	if !l.TryLock() {
+		return false
+	}
+	defer l.Unlock()
+	f()
+	return true
+}
@@ -0,0 +1,127 @@
+// Package tmp provides scoped management of temporary files and directories.
+//
+// A typical use looks like:
+//
+//	func run() (err error) {
+//	  s := tmp.New("")
+//	  defer s.CloseErr(&err)
+//	  f, err := s.File("data-*.txt")
+//	  ...
+//	}
+package tmp
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+)
+
+// A Scope creates temporary files and directories, and removes all of them
+// when closed. Safe for concurrent use.
+type Scope struct {
+	// If true, Close does not remove anything if the scope failed.
+	// Useful for debugging batch jobs.
+	KeepOnFailure bool
+
+	dir    string
+	mu     sync.Mutex
+	paths  []string
+	files  []*os.File
+	failed bool
+	closed bool
+}
+
+// New returns a scope that creates its artifacts in the given directory.
+// If dir is empty, uses [os.TempDir].
+func New(dir string) *Scope {
+	return &Scope{dir: dir}
+}
+
+// File creates a new temporary file, as in [os.CreateTemp].
+// The file is closed and removed when the scope is closed.
+func (s *Scope) File(pattern string) (*os.File, error) {
+	f, err := os.CreateTemp(s.dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, f.Name())
+	s.files = append(s.files, f)
+	return f, nil
+}
+
+// Dir creates a new temporary directory, as in [os.MkdirTemp],
+// and returns its path.
+// The directory and its contents are removed when the scope is closed.
+func (s *Scope) Dir(pattern string) (string, error) {
+	d, err := os.MkdirTemp(s.dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	s.Track(d)
+	return d, nil
+}
+
+// Track adds an existing path to be removed when the scope is closed.
+func (s *Scope) Track(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, path)
+}
+
+// Paths returns the paths tracked by the scope, in creation order.
+func (s *Scope) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.paths)
+}
+
+// Fail marks the scope as failed.
+func (s *Scope) Fail() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = true
+}
+
+// Close closes the scope's files and removes all of its paths, in reverse
+// creation order. If the scope failed and KeepOnFailure is set, keeps
+// everything, and the kept paths are available through Paths.
+// Returns the joined errors of all removals.
+// Calling Close more than once has no effect.
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	var errs []error
+	for _, f := range s.files {
+		if err := f.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	if s.failed && s.KeepOnFailure {
+		return errors.Join(errs...)
+	}
+	for _, p := range slices.Backward(s.paths) {
+		if err := os.RemoveAll(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CloseErr marks the scope as failed if *err is not nil, closes the scope,
+// and joins the closing error into *err. Meant to be deferred in functions
+// with a named error result.
+func (s *Scope) CloseErr(err *error) {
+	if *err != nil {
+		s.Fail()
+	}
+	if cerr := s.Close(); cerr != nil {
+		*err = errors.Join(*err, cerr)
+	}
+}
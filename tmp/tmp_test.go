@@ -0,0 +1,74 @@
+package tmp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	s := New(t.TempDir())
+	f, err := s.File("a-*.txt")
+	if err != nil {
+		t.Fatalf("File() failed: %v", err)
+	}
+	f.WriteString("hello")
+	d, err := s.Dir("b-*")
+	if err != nil {
+		t.Fatalf("Dir() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "c"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	paths := s.Paths()
+	if len(paths) != 2 {
+		t.Fatalf("Paths()=%v, want 2 paths", paths)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	for _, p := range paths {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("Stat(%q) after Close()=%v, want not exist", p, err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("second Close() failed: %v", err)
+	}
+}
+
+func TestScope_keepOnFailure(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		s := New(t.TempDir())
+		s.KeepOnFailure = keep
+		d, _ := s.Dir("")
+		err := func() (err error) {
+			defer s.CloseErr(&err)
+			return errors.New("oops")
+		}()
+		if err == nil {
+			t.Errorf("CloseErr(...)=nil, want error")
+		}
+		_, serr := os.Stat(d)
+		if exists := serr == nil; exists != keep {
+			t.Errorf("KeepOnFailure=%v: exists=%v, want %v", keep, exists, keep)
+		}
+	}
+}
+
+func TestScope_noFailure(t *testing.T) {
+	s := New(t.TempDir())
+	s.KeepOnFailure = true
+	d, _ := s.Dir("")
+	err := func() (err error) {
+		defer s.CloseErr(&err)
+		return nil
+	}()
+	if err != nil {
+		t.Errorf("CloseErr(...)=%v, want nil", err)
+	}
+	if _, err := os.Stat(d); !os.IsNotExist(err) {
+		t.Errorf("Stat(%q)=%v, want not exist", d, err)
+	}
+}
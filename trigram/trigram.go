@@ -0,0 +1,128 @@
+// Package trigram provides fuzzy string search using trigram similarity.
+//
+// The similarity of two strings is the Jaccard similarity of their trigram
+// sets. Strings are lowercased and padded with two spaces at the start and
+// one at the end, so that short strings and word boundaries are
+// represented.
+package trigram
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/fluhus/gostuff/sets"
+)
+
+// Trigrams returns the distinct trigrams of s, in no particular order.
+func Trigrams(s string) []string {
+	r := []rune("  " + strings.ToLower(s) + " ")
+	set := sets.Set[string]{}
+	for i := range len(r) - 2 {
+		set.Add(string(r[i : i+3]))
+	}
+	result := make([]string, 0, len(set))
+	for t := range set {
+		result = append(result, t)
+	}
+	return result
+}
+
+// Similarity returns the trigram similarity of a and b, between 0 and 1.
+func Similarity(a, b string) float64 {
+	ta := sets.Set[string]{}.Add(Trigrams(a)...)
+	tb := Trigrams(b)
+	common := 0
+	for _, t := range tb {
+		if ta.Has(t) {
+			common++
+		}
+	}
+	return jaccard(common, len(ta), len(tb))
+}
+
+// An Index stores strings by their trigrams for fuzzy search.
+type Index struct {
+	strs  []string
+	ntri  []int            // Number of trigrams in each string.
+	posts map[string][]int // IDs of the strings that have each trigram.
+}
+
+// A Result is a single search result.
+type Result struct {
+	ID         int     // ID of the found string, as returned by Add.
+	Value      string  // The found string.
+	Similarity float64 // Trigram similarity to the query.
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{posts: map[string][]int{}}
+}
+
+// Len returns the number of strings in the index.
+func (x *Index) Len() int {
+	return len(x.strs)
+}
+
+// At returns the string with the given ID.
+func (x *Index) At(id int) string {
+	return x.strs[id]
+}
+
+// Add inserts s to the index and returns its ID.
+// IDs are sequential, starting at 0.
+func (x *Index) Add(s string) int {
+	id := len(x.strs)
+	tri := Trigrams(s)
+	x.strs = append(x.strs, s)
+	x.ntri = append(x.ntri, len(tri))
+	for _, t := range tri {
+		x.posts[t] = append(x.posts[t], id)
+	}
+	return id
+}
+
+// Search returns the strings whose similarity to the query is at least
+// minSim, ordered by descending similarity. minSim should be positive.
+//
+// Only strings that share enough trigrams with the query to possibly reach
+// minSim are scored.
+func (x *Index) Search(query string, minSim float64) []Result {
+	qtri := Trigrams(query)
+
+	// Count shared trigrams per candidate.
+	counts := map[int]int{}
+	for _, t := range qtri {
+		for _, id := range x.posts[t] {
+			counts[id]++
+		}
+	}
+
+	// Similarity is at most common/len(qtri), which allows filtering
+	// before scoring.
+	minCommon := minSim * float64(len(qtri))
+	var result []Result
+	for id, c := range counts {
+		if float64(c) < minCommon {
+			continue
+		}
+		if sim := jaccard(c, len(qtri), x.ntri[id]); sim >= minSim {
+			result = append(result, Result{id, x.strs[id], sim})
+		}
+	}
+	slices.SortFunc(result, func(a, b Result) int {
+		return cmp.Or(cmp.Compare(b.Similarity, a.Similarity),
+			cmp.Compare(a.ID, b.ID))
+	})
+	return result
+}
+
+// Returns the Jaccard similarity of two sets with the given sizes and
+// intersection size.
+func jaccard(common, na, nb int) float64 {
+	if na+nb == 0 {
+		return 1
+	}
+	return float64(common) / float64(na+nb-common)
+}
@@ -0,0 +1,89 @@
+package trigram
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTrigrams(t *testing.T) {
+	got := Trigrams("Cat")
+	slices.Sort(got)
+	want := []string{"  c", " ca", "at ", "cat"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Trigrams(%q)=%q, want %q", "Cat", got, want)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"cat", "cat", 1},
+		{"cat", "CAT", 1},
+		{"cat", "dog", 0},
+		{"cat", "cart", 2.0 / 7},
+		{"", "", 1},
+	}
+	for _, test := range tests {
+		if got := Similarity(test.a, test.b); got != test.want {
+			t.Errorf("Similarity(%q,%q)=%v, want %v",
+				test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	x := New()
+	for _, s := range []string{"john smith", "jon smyth", "jane doe",
+		"johnny smithers", "bob"} {
+		x.Add(s)
+	}
+	res := x.Search("john smith", 0.3)
+	if len(res) == 0 || res[0].Value != "john smith" || res[0].Similarity != 1 {
+		t.Fatalf("Search(...)=%v, want exact match first", res)
+	}
+	for _, r := range res {
+		if r.Value == "bob" || r.Value == "jane doe" {
+			t.Errorf("Search(...) returned unrelated %q", r.Value)
+		}
+	}
+}
+
+func TestSearchBruteForce(t *testing.T) {
+	const letters = "abcde"
+	randStr := func() string {
+		b := make([]byte, 2+rand.IntN(8))
+		for i := range b {
+			b[i] = letters[rand.IntN(len(letters))]
+		}
+		return string(b)
+	}
+	x := New()
+	for range 300 {
+		x.Add(randStr())
+	}
+	for range 50 {
+		q := randStr()
+		minSim := rand.Float64()*0.8 + 0.1
+		var want []int
+		for id := range x.Len() {
+			if Similarity(q, x.At(id)) >= minSim {
+				want = append(want, id)
+			}
+		}
+		res := x.Search(q, minSim)
+		var got []int
+		for i, r := range res {
+			got = append(got, r.ID)
+			if i > 0 && r.Similarity > res[i-1].Similarity {
+				t.Fatalf("Search(%q) not sorted: %v", q, res)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("Search(%q,%v)=%v, want %v", q, minSim, got, want)
+		}
+	}
+}